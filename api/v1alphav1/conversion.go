@@ -0,0 +1,333 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/InditexTech/k8s-overcommit-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+const (
+	// ExcludedNamespacesAnnotation keeps the deprecated excludedNamespaces regex of a class
+	// read or written through v1, which has no field for it.
+	ExcludedNamespacesAnnotation = "overcommit.inditex.dev/excluded-namespaces"
+	// RatiosAnnotation keeps the ratios that can't be written exactly as a quantity, so
+	// that converting a class to v1 and back returns the same ratios.
+	RatiosAnnotation = "overcommit.inditex.dev/v1alphav1-ratios"
+)
+
+var _ conversion.Convertible = &OvercommitClass{}
+
+// ConvertTo converts this OvercommitClass to the hub version (v1).
+func (src *OvercommitClass) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1.OvercommitClass)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	delete(dst.Annotations, ExcludedNamespacesAnnotation)
+	delete(dst.Annotations, RatiosAnnotation)
+
+	ratios := map[corev1.ResourceName]float64{
+		corev1.ResourceCPU:    src.Spec.CpuOvercommit,
+		corev1.ResourceMemory: src.Spec.MemoryOvercommit,
+	}
+	if src.Spec.EphemeralStorageOvercommit != 0 {
+		ratios[corev1.ResourceEphemeralStorage] = src.Spec.EphemeralStorageOvercommit
+	}
+
+	inexact := map[corev1.ResourceName]string{}
+	dst.Spec.Resources = make(map[corev1.ResourceName]resource.Quantity, len(ratios))
+	for name, ratio := range ratios {
+		quantity, err := toQuantity(ratio)
+		if err != nil {
+			return fmt.Errorf("error converting the %s ratio of %s: %w", name, src.Name, err)
+		}
+		if back, err := toFloat(quantity); err != nil || back != ratio {
+			inexact[name] = strconv.FormatFloat(ratio, 'g', -1, 64)
+		}
+		dst.Spec.Resources[name] = quantity
+	}
+	if len(inexact) > 0 {
+		raw, err := json.Marshal(inexact)
+		if err != nil {
+			return err
+		}
+		setAnnotation(&dst.ObjectMeta.Annotations, RatiosAnnotation, string(raw))
+	}
+	if src.Spec.ExcludedNamespaces != "" {
+		setAnnotation(&dst.ObjectMeta.Annotations, ExcludedNamespacesAnnotation, src.Spec.ExcludedNamespaces)
+	}
+
+	dst.Spec.Baseline = convertMap[corev1.ResourceName, BaselineMode, v1.BaselineMode](src.Spec.Baseline)
+	dst.Spec.MinRequest = src.Spec.MinRequest.DeepCopy()
+	dst.Spec.MaxRequest = src.Spec.MaxRequest.DeepCopy()
+	if src.Spec.Rounding != nil {
+		dst.Spec.Rounding = &v1.RoundingSpec{
+			CpuMillicores: src.Spec.Rounding.CpuMillicores,
+			Memory:        v1.MemoryRounding(src.Spec.Rounding.Memory),
+		}
+	}
+	dst.Spec.RequestPolicy = v1.RequestPolicy(src.Spec.RequestPolicy)
+	dst.Spec.Mode = v1.Mode(src.Spec.Mode)
+	dst.Spec.OnError = v1.OnError(src.Spec.OnError)
+	dst.Spec.NamespaceSelector = src.Spec.NamespaceSelector.DeepCopy()
+	dst.Spec.ExcludedNamespaceNames = append([]string(nil), src.Spec.ExcludedNamespaceNames...)
+	dst.Spec.PodSelector = src.Spec.PodSelector.DeepCopy()
+	for _, condition := range src.Spec.MatchConditions {
+		dst.Spec.MatchConditions = append(dst.Spec.MatchConditions, *condition.DeepCopy())
+	}
+	if src.Spec.Exclusions != nil {
+		dst.Spec.Exclusions = &v1.ExclusionsSpec{
+			OwnerKinds:           append([]string(nil), src.Spec.Exclusions.OwnerKinds...),
+			PriorityClassNames:   append([]string(nil), src.Spec.Exclusions.PriorityClassNames...),
+			Guaranteed:           src.Spec.Exclusions.Guaranteed,
+			ServiceAccountOptOut: src.Spec.Exclusions.ServiceAccountOptOut,
+		}
+	}
+	for _, rule := range src.Spec.ContainerRules {
+		converted := v1.ContainerRule{
+			Name:          rule.Name,
+			ContainerName: rule.ContainerName,
+			Image:         rule.Image,
+			Exclude:       rule.Exclude,
+			MinRequest:    rule.MinRequest.DeepCopy(),
+		}
+		ratios, err := toQuantities(rule.Ratios)
+		if err != nil {
+			return fmt.Errorf("error converting the ratios of the rule %s of %s: %w", rule.Name, src.Name, err)
+		}
+		converted.Ratios = ratios
+		dst.Spec.ContainerRules = append(dst.Spec.ContainerRules, converted)
+	}
+	if src.Spec.InitContainers != nil {
+		ratios, err := toQuantities(src.Spec.InitContainers.Ratios)
+		if err != nil {
+			return fmt.Errorf("error converting the init container ratios of %s: %w", src.Name, err)
+		}
+		dst.Spec.InitContainers = &v1.InitContainersSpec{Skip: src.Spec.InitContainers.Skip, Ratios: ratios}
+	}
+	dst.Spec.IsDefault = src.Spec.IsDefault
+	dst.Spec.Labels = convertMap[string, string, string](src.Spec.Labels)
+	dst.Spec.Annotations = convertMap[string, string, string](src.Spec.Annotations)
+	if deployment := src.Spec.Deployment.DeepCopy(); deployment != nil {
+		dst.Spec.Deployment = &v1.DeploymentTemplate{
+			Replicas:                  deployment.Replicas,
+			Resources:                 deployment.Resources,
+			NodeSelector:              deployment.NodeSelector,
+			Tolerations:               deployment.Tolerations,
+			Affinity:                  deployment.Affinity,
+			TopologySpreadConstraints: deployment.TopologySpreadConstraints,
+			PriorityClassName:         deployment.PriorityClassName,
+			SecurityContext:           deployment.SecurityContext,
+			ContainerSecurityContext:  deployment.ContainerSecurityContext,
+		}
+		if budget := deployment.PodDisruptionBudget; budget != nil {
+			converted := v1.PodDisruptionBudgetTemplate(*budget)
+			dst.Spec.Deployment.PodDisruptionBudget = &converted
+		}
+	}
+
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	for _, status := range src.Status.Resources {
+		dst.Status.Resources = append(dst.Status.Resources, v1.ResourceStatus(status))
+	}
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, *condition.DeepCopy())
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1) to this version.
+func (dst *OvercommitClass) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1.OvercommitClass)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	delete(dst.Annotations, ExcludedNamespacesAnnotation)
+	delete(dst.Annotations, RatiosAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	exact := map[corev1.ResourceName]string{}
+	if raw, ok := src.Annotations[RatiosAnnotation]; ok {
+		if err := json.Unmarshal([]byte(raw), &exact); err != nil {
+			return fmt.Errorf("error reading the %s annotation of %s: %w", RatiosAnnotation, src.Name, err)
+		}
+	}
+
+	dst.Spec = OvercommitClassSpec{}
+	for name, quantity := range src.Spec.Resources {
+		ratio, err := toFloat(quantity)
+		if err != nil {
+			return fmt.Errorf("error converting the %s ratio of %s: %w", name, src.Name, err)
+		}
+		// The exact ratio is only used while the quantity still matches it, a class
+		// updated through v1 keeps the new quantity.
+		if value, ok := exact[name]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				if rounded, err := toQuantity(parsed); err == nil && rounded.Cmp(quantity) == 0 {
+					ratio = parsed
+				}
+			}
+		}
+
+		switch name {
+		case corev1.ResourceCPU:
+			dst.Spec.CpuOvercommit = ratio
+		case corev1.ResourceMemory:
+			dst.Spec.MemoryOvercommit = ratio
+		case corev1.ResourceEphemeralStorage:
+			dst.Spec.EphemeralStorageOvercommit = ratio
+		default:
+			return fmt.Errorf("error converting the ratios of %s: %s can't be overcommitted", src.Name, name)
+		}
+	}
+	dst.Spec.ExcludedNamespaces = src.Annotations[ExcludedNamespacesAnnotation]
+
+	dst.Spec.Baseline = convertMap[corev1.ResourceName, v1.BaselineMode, BaselineMode](src.Spec.Baseline)
+	dst.Spec.MinRequest = src.Spec.MinRequest.DeepCopy()
+	dst.Spec.MaxRequest = src.Spec.MaxRequest.DeepCopy()
+	if src.Spec.Rounding != nil {
+		dst.Spec.Rounding = &RoundingSpec{
+			CpuMillicores: src.Spec.Rounding.CpuMillicores,
+			Memory:        MemoryRounding(src.Spec.Rounding.Memory),
+		}
+	}
+	dst.Spec.RequestPolicy = RequestPolicy(src.Spec.RequestPolicy)
+	dst.Spec.Mode = Mode(src.Spec.Mode)
+	dst.Spec.OnError = OnError(src.Spec.OnError)
+	dst.Spec.NamespaceSelector = src.Spec.NamespaceSelector.DeepCopy()
+	dst.Spec.ExcludedNamespaceNames = append([]string(nil), src.Spec.ExcludedNamespaceNames...)
+	dst.Spec.PodSelector = src.Spec.PodSelector.DeepCopy()
+	for _, condition := range src.Spec.MatchConditions {
+		dst.Spec.MatchConditions = append(dst.Spec.MatchConditions, *condition.DeepCopy())
+	}
+	if src.Spec.Exclusions != nil {
+		dst.Spec.Exclusions = &ExclusionsSpec{
+			OwnerKinds:           append([]string(nil), src.Spec.Exclusions.OwnerKinds...),
+			PriorityClassNames:   append([]string(nil), src.Spec.Exclusions.PriorityClassNames...),
+			Guaranteed:           src.Spec.Exclusions.Guaranteed,
+			ServiceAccountOptOut: src.Spec.Exclusions.ServiceAccountOptOut,
+		}
+	}
+	for _, rule := range src.Spec.ContainerRules {
+		converted := ContainerRule{
+			Name:          rule.Name,
+			ContainerName: rule.ContainerName,
+			Image:         rule.Image,
+			Exclude:       rule.Exclude,
+			MinRequest:    rule.MinRequest.DeepCopy(),
+		}
+		ratios, err := toFloats(rule.Ratios)
+		if err != nil {
+			return fmt.Errorf("error converting the ratios of the rule %s of %s: %w", rule.Name, src.Name, err)
+		}
+		converted.Ratios = ratios
+		dst.Spec.ContainerRules = append(dst.Spec.ContainerRules, converted)
+	}
+	if src.Spec.InitContainers != nil {
+		ratios, err := toFloats(src.Spec.InitContainers.Ratios)
+		if err != nil {
+			return fmt.Errorf("error converting the init container ratios of %s: %w", src.Name, err)
+		}
+		dst.Spec.InitContainers = &InitContainersSpec{Skip: src.Spec.InitContainers.Skip, Ratios: ratios}
+	}
+	dst.Spec.IsDefault = src.Spec.IsDefault
+	dst.Spec.Labels = convertMap[string, string, string](src.Spec.Labels)
+	dst.Spec.Annotations = convertMap[string, string, string](src.Spec.Annotations)
+	if deployment := src.Spec.Deployment.DeepCopy(); deployment != nil {
+		dst.Spec.Deployment = &DeploymentTemplate{
+			Replicas:                  deployment.Replicas,
+			Resources:                 deployment.Resources,
+			NodeSelector:              deployment.NodeSelector,
+			Tolerations:               deployment.Tolerations,
+			Affinity:                  deployment.Affinity,
+			TopologySpreadConstraints: deployment.TopologySpreadConstraints,
+			PriorityClassName:         deployment.PriorityClassName,
+			SecurityContext:           deployment.SecurityContext,
+			ContainerSecurityContext:  deployment.ContainerSecurityContext,
+		}
+		if budget := deployment.PodDisruptionBudget; budget != nil {
+			converted := PodDisruptionBudgetTemplate(*budget)
+			dst.Spec.Deployment.PodDisruptionBudget = &converted
+		}
+	}
+
+	dst.Status = OvercommitClassStatus{ObservedGeneration: src.Status.ObservedGeneration}
+	for _, status := range src.Status.Resources {
+		dst.Status.Resources = append(dst.Status.Resources, ResourceStatus(status))
+	}
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, *condition.DeepCopy())
+	}
+	return nil
+}
+
+// toQuantity writes a ratio as a quantity, e.g. 0.25 as "250m".
+func toQuantity(ratio float64) (resource.Quantity, error) {
+	return resource.ParseQuantity(strconv.FormatFloat(ratio, 'f', -1, 64))
+}
+
+// toFloat is the inverse of toQuantity.
+func toFloat(quantity resource.Quantity) (float64, error) {
+	return strconv.ParseFloat(quantity.AsDec().String(), 64)
+}
+
+// toQuantities writes the ratios as quantities. They have at most 4 decimals, unlike the
+// ratios of the class before the validation of the decimals, so they are written exactly.
+func toQuantities(ratios map[corev1.ResourceName]float64) (map[corev1.ResourceName]resource.Quantity, error) {
+	if ratios == nil {
+		return nil, nil
+	}
+	quantities := make(map[corev1.ResourceName]resource.Quantity, len(ratios))
+	for name, ratio := range ratios {
+		quantity, err := toQuantity(ratio)
+		if err != nil {
+			return nil, fmt.Errorf("error converting the %s ratio: %w", name, err)
+		}
+		quantities[name] = quantity
+	}
+	return quantities, nil
+}
+
+// toFloats is the inverse of toQuantities.
+func toFloats(quantities map[corev1.ResourceName]resource.Quantity) (map[corev1.ResourceName]float64, error) {
+	if quantities == nil {
+		return nil, nil
+	}
+	ratios := make(map[corev1.ResourceName]float64, len(quantities))
+	for name, quantity := range quantities {
+		ratio, err := toFloat(quantity)
+		if err != nil {
+			return nil, fmt.Errorf("error converting the %s ratio: %w", name, err)
+		}
+		ratios[name] = ratio
+	}
+	return ratios, nil
+}
+
+func setAnnotation(annotations *map[string]string, key, value string) {
+	if *annotations == nil {
+		*annotations = map[string]string{}
+	}
+	(*annotations)[key] = value
+}
+
+func convertMap[K comparable, From, To ~string](src map[K]From) map[K]To {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[K]To, len(src))
+	for key, value := range src {
+		dst[key] = To(value)
+	}
+	return dst
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	v1 "github.com/InditexTech/k8s-overcommit-operator/api/v1"
+)
+
+// quantity matches a quantity equal to value, whatever its format.
+func quantity(value string) OmegaMatcher {
+	return WithTransform(func(q resource.Quantity) int { return q.Cmp(resource.MustParse(value)) }, BeZero())
+}
+
+var _ = Describe("OvercommitClass conversion", func() {
+	var class *OvercommitClass
+
+	BeforeEach(func() {
+		class = &OvercommitClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-class",
+				Annotations: map[string]string{"owner": "platform"},
+			},
+			Spec: OvercommitClassSpec{
+				CpuOvercommit:              0.25,
+				MemoryOvercommit:           0.5,
+				EphemeralStorageOvercommit: 0.1,
+				Baseline:                   map[corev1.ResourceName]BaselineMode{corev1.ResourceCPU: BaselineFromRequests},
+				MinRequest:                 corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+				Rounding:                   &RoundingSpec{Memory: MemoryRoundingMi},
+				RequestPolicy:              RequestPolicyOnlyLower,
+				Mode:                       ModeAudit,
+				OnError:                    OnErrorDeny,
+				ExcludedNamespaces:         "^kube-.*",
+				ExcludedNamespaceNames:     []string{"monitoring"},
+				PodSelector:                &metav1.LabelSelector{MatchLabels: map[string]string{"workload": "batch"}},
+				MatchConditions:            []admissionv1.MatchCondition{{Name: "high", Expression: "true"}},
+				Exclusions:                 &ExclusionsSpec{OwnerKinds: []string{"DaemonSet"}, Guaranteed: true, ServiceAccountOptOut: true},
+				InitContainers:             &InitContainersSpec{Ratios: map[corev1.ResourceName]float64{corev1.ResourceMemory: 0.9}},
+				ContainerRules: []ContainerRule{
+					{Name: "istio", ContainerName: "istio-proxy", Ratios: map[corev1.ResourceName]float64{corev1.ResourceCPU: 0.1}},
+					{Name: "jvm", Image: "*/java:*", MinRequest: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")}},
+				},
+				IsDefault: true,
+				Labels:    map[string]string{"example": "label"},
+				Deployment: &DeploymentTemplate{
+					Replicas:            ptr.To(int32(2)),
+					PriorityClassName:   "high",
+					PodDisruptionBudget: &PodDisruptionBudgetTemplate{MinAvailable: ptr.To(intstr.FromInt32(1))},
+				},
+			},
+		}
+	})
+
+	It("should write the ratios as quantities", func() {
+		hub := &v1.OvercommitClass{}
+		Expect(class.ConvertTo(hub)).To(Succeed())
+
+		Expect(hub.Spec.Resources).To(HaveLen(3))
+		Expect(hub.Spec.Resources).To(HaveKeyWithValue(corev1.ResourceCPU, quantity("250m")))
+		Expect(hub.Spec.Resources).To(HaveKeyWithValue(corev1.ResourceMemory, quantity("0.5")))
+		Expect(hub.Spec.Resources).To(HaveKeyWithValue(corev1.ResourceEphemeralStorage, quantity("100m")))
+		Expect(hub.Annotations).To(HaveKeyWithValue(ExcludedNamespacesAnnotation, "^kube-.*"))
+		Expect(hub.Annotations).NotTo(HaveKey(RatiosAnnotation))
+	})
+
+	It("should convert to v1 and back without losing anything", func() {
+		hub := &v1.OvercommitClass{}
+		Expect(class.ConvertTo(hub)).To(Succeed())
+
+		back := &OvercommitClass{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		Expect(back).To(Equal(class))
+	})
+
+	It("should keep the ratios that aren't exact quantities", func() {
+		class.Spec.CpuOvercommit = 0.1234567890123
+		hub := &v1.OvercommitClass{}
+		Expect(class.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).To(HaveKey(RatiosAnnotation))
+
+		back := &OvercommitClass{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		Expect(back).To(Equal(class))
+	})
+
+	It("should use the quantity when it is changed in v1", func() {
+		class.Spec.CpuOvercommit = 0.1234567890123
+		hub := &v1.OvercommitClass{}
+		Expect(class.ConvertTo(hub)).To(Succeed())
+		hub.Spec.Resources[corev1.ResourceCPU] = resource.MustParse("0.3")
+
+		back := &OvercommitClass{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		Expect(back.Spec.CpuOvercommit).To(Equal(0.3))
+		Expect(back.Annotations).NotTo(HaveKey(RatiosAnnotation))
+	})
+})
@@ -6,7 +6,11 @@
 package v1alphav1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -24,18 +28,265 @@ type OvercommitSpec struct {
 	Labels map[string]string `json:"labels,omitempty"`
 	// +kubebuilder:validation:Optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// MutatingWebhook defines how the pod mutating webhook is deployed.
+	// +optional
+	MutatingWebhook *MutatingWebhookSpec `json:"mutatingWebhook,omitempty"`
+	// LabelMigration defines how pods and namespaces move to a new overcommitLabel.
+	// +optional
+	LabelMigration *LabelMigrationSpec `json:"labelMigration,omitempty"`
+	// Deployment customizes every Deployment generated by the operator. The webhook Deployment
+	// of a class can override it.
+	// +optional
+	Deployment *DeploymentTemplate `json:"deployment,omitempty"`
+	// Certificates defines how the serving certificates of the webhooks are issued.
+	// +optional
+	Certificates *CertificatesSpec `json:"certificates,omitempty"`
+}
+
+// CertificateProvider issues the serving certificates of the webhooks and injects the CA bundle
+// that verifies them.
+// +kubebuilder:validation:Enum=cert-manager;builtin;external;service-ca
+type CertificateProvider string
+
+const (
+	// CertificateProviderCertManager issues the certificates with a self-signed cert-manager
+	// Issuer, and cert-manager injects the CA bundle.
+	CertificateProviderCertManager CertificateProvider = "cert-manager"
+	// CertificateProviderBuiltin issues the certificates with a CA generated by the operator,
+	// rotates them before they expire and injects the CA bundle.
+	CertificateProviderBuiltin CertificateProvider = "builtin"
+	// CertificateProviderExternal uses the certificates of the secrets created by the user and
+	// injects the CA bundle of the spec.
+	CertificateProviderExternal CertificateProvider = "external"
+	// CertificateProviderServiceCA issues the certificates with the service CA of OpenShift,
+	// which rotates them and injects the CA bundle.
+	CertificateProviderServiceCA CertificateProvider = "service-ca"
+)
+
+// CertificatesSpec defines how the serving certificates of the webhooks are issued.
+// +kubebuilder:validation:XValidation:rule="self.provider != 'external' || (has(self.external) && size(self.external.caBundle) > 0)",message="the external provider requires external.caBundle"
+type CertificatesSpec struct {
+	// Provider of the certificates.
+	// +kubebuilder:default=cert-manager
+	// +optional
+	Provider CertificateProvider `json:"provider,omitempty"`
+	// Builtin configures the builtin provider.
+	// +optional
+	Builtin *BuiltinCertificatesSpec `json:"builtin,omitempty"`
+	// External configures the external provider.
+	// +optional
+	External *ExternalCertificatesSpec `json:"external,omitempty"`
+}
+
+// BuiltinCertificatesSpec configures the CA generated by the builtin provider.
+type BuiltinCertificatesSpec struct {
+	// CAValidity is how long the generated CA is valid.
+	// +kubebuilder:default="87600h"
+	// +optional
+	CAValidity *metav1.Duration `json:"caValidity,omitempty"`
+	// CARenewBefore is how long before it expires the CA is rotated. The previous CA stays in
+	// the CA bundle until it expires.
+	// +kubebuilder:default="720h"
+	// +optional
+	CARenewBefore *metav1.Duration `json:"caRenewBefore,omitempty"`
+}
+
+// ExternalCertificatesSpec configures the external provider. The user creates a kubernetes.io/tls
+// secret with the name of every webhook secret.
+type ExternalCertificatesSpec struct {
+	// CABundle is the PEM encoded bundle of the CAs that verify the certificates of the secrets.
+	CABundle []byte `json:"caBundle"`
+}
+
+// WebhookTopology defines how many deployments serve the pod mutating webhook.
+// +kubebuilder:validation:Enum=PerClass;Consolidated
+type WebhookTopology string
+
+const (
+	// WebhookTopologyPerClass deploys a webhook Deployment, Service and Certificate for every
+	// OvercommitClass.
+	WebhookTopologyPerClass WebhookTopology = "PerClass"
+	// WebhookTopologyConsolidated deploys a single webhook Deployment, Service and Certificate
+	// that serves every OvercommitClass.
+	WebhookTopologyConsolidated WebhookTopology = "Consolidated"
+)
+
+// MutatingWebhookSpec defines how the pod mutating webhook is deployed.
+type MutatingWebhookSpec struct {
+	// Topology selects a webhook deployment per class or a single one shared by every class.
+	// Every class keeps its own MutatingWebhookConfiguration in both topologies.
+	// +kubebuilder:default=PerClass
+	// +optional
+	Topology WebhookTopology `json:"topology,omitempty"`
+	// Replicas of the shared webhook deployment of the Consolidated topology.
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// DeploymentTemplate customizes the Deployments generated by the operator. Unset fields keep the
+// defaults of the operator.
+type DeploymentTemplate struct {
+	// Replicas of the Deployment.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Resources of the containers.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// NodeSelector of the pods.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity of the pods. The pods of a Deployment prefer different nodes when it isn't set.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// TopologySpreadConstraints of the pods.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName of the pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// SecurityContext of the pods.
+	// +optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// ContainerSecurityContext of the containers.
+	// +optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// PodDisruptionBudget generated for the webhook Deployments. It allows one unavailable pod
+	// when it isn't set.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetTemplate `json:"podDisruptionBudget,omitempty"`
+}
+
+// PodDisruptionBudgetTemplate customizes the PodDisruptionBudgets generated by the operator.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetTemplate struct {
+	// MinAvailable pods of the Deployment during a voluntary disruption.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable pods of the Deployment during a voluntary disruption.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// NamespaceRelabel defines whether the namespaces labeled with the previous overcommitLabel are
+// relabeled with the new one.
+// +kubebuilder:validation:Enum=Disabled;DryRun;Enabled
+type NamespaceRelabel string
+
+const (
+	// NamespaceRelabelDisabled leaves the labels of the namespaces untouched.
+	NamespaceRelabelDisabled NamespaceRelabel = "Disabled"
+	// NamespaceRelabelDryRun lists in the status the namespaces that would be relabeled.
+	NamespaceRelabelDryRun NamespaceRelabel = "DryRun"
+	// NamespaceRelabelEnabled moves the value of the previous label of the namespaces to the new one.
+	NamespaceRelabelEnabled NamespaceRelabel = "Enabled"
+)
+
+// LabelMigrationSpec defines how pods and namespaces move to a new overcommitLabel.
+type LabelMigrationSpec struct {
+	// GracePeriod during which both the previous and the new overcommitLabel select the class
+	// of pods and namespaces after overcommitLabel changes.
+	// +kubebuilder:default="24h"
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	// NamespaceRelabel defines whether the namespaces labeled with the previous overcommitLabel
+	// are relabeled with the new one during the grace period.
+	// +kubebuilder:default=Disabled
+	// +optional
+	NamespaceRelabel NamespaceRelabel `json:"namespaceRelabel,omitempty"`
 }
 
+// DefaultLabelMigrationGracePeriod is the grace period of a label migration when it isn't set.
+const DefaultLabelMigrationGracePeriod = 24 * time.Hour
+
+// Validity and renewal of the CA of the builtin certificate provider when they aren't set.
+const (
+	DefaultCAValidity    = 87600 * time.Hour
+	DefaultCARenewBefore = 720 * time.Hour
+)
+
+// LabelMigrationPhase is the phase of a label migration.
+type LabelMigrationPhase string
+
+const (
+	// LabelMigrationInProgress is the phase of a migration within its grace period.
+	LabelMigrationInProgress LabelMigrationPhase = "InProgress"
+	// LabelMigrationCompleted is the phase of a migration after its grace period, once only
+	// the new label is honored.
+	LabelMigrationCompleted LabelMigrationPhase = "Completed"
+)
+
+// LabelMigrationStatus is the progress of the migration to a new overcommitLabel.
+type LabelMigrationStatus struct {
+	// From is the previous overcommitLabel.
+	From string `json:"from"`
+	// To is the new overcommitLabel.
+	To string `json:"to"`
+	// Phase of the migration.
+	Phase LabelMigrationPhase `json:"phase"`
+	// StartTime is when the change of overcommitLabel was observed.
+	StartTime metav1.Time `json:"startTime"`
+	// Deadline is the end of the grace period, after which only the new label is honored.
+	Deadline metav1.Time `json:"deadline"`
+	// PendingPods is the number of pods labeled with the previous label and not with the new one.
+	// +optional
+	PendingPods int32 `json:"pendingPods,omitempty"`
+	// PendingNamespaces is the number of namespaces labeled with the previous label and not with
+	// the new one.
+	// +optional
+	PendingNamespaces int32 `json:"pendingNamespaces,omitempty"`
+	// NamespacesToRelabel lists the pending namespaces in the DryRun mode of namespaceRelabel.
+	// +optional
+	NamespacesToRelabel []string `json:"namespacesToRelabel,omitempty"`
+	// RelabeledNamespaces is the number of namespaces relabeled in the Enabled mode of
+	// namespaceRelabel.
+	// +optional
+	RelabeledNamespaces int32 `json:"relabeledNamespaces,omitempty"`
+}
+
+// Condition types of the status of the Overcommit and OvercommitClass resources
+const (
+	// ConditionResourcesReady is true when every generated resource is ready
+	ConditionResourcesReady = "ResourcesReady"
+	// ConditionAvailable is true when every webhook and controller Deployment is available
+	ConditionAvailable = "Available"
+	// ConditionProgressing is true while a generated resource is being created, rolled out or issued
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is true when a generated resource failed and is not progressing
+	ConditionDegraded = "Degraded"
+	// ConditionWebhookReachable is true when every webhook configuration has its CA bundle and
+	// ready endpoints behind its Service
+	ConditionWebhookReachable = "WebhookReachable"
+)
+
 // OvercommitStatus defines the observed state of Overcommit
 type OvercommitStatus struct {
-	Resources  []ResourceStatus   `json:"resources,omitempty"`
-	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the Overcommit resource the status was computed for
+	// +optional
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Resources          []ResourceStatus   `json:"resources,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	// OvercommitLabel is the overcommitLabel the generated resources were last reconciled with
+	// +optional
+	OvercommitLabel string `json:"overcommitLabel,omitempty"`
+	// LabelMigration is the progress of the last change of overcommitLabel
+	// +optional
+	LabelMigration *LabelMigrationStatus `json:"labelMigration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Target Label",type=string,JSONPath=".spec.overcommitLabel",description="Label to apply to the pods to make overcommit"
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`,description="Every webhook and controller deployment is available"
+// +kubebuilder:printcolumn:name="Reachable",type=string,JSONPath=`.status.conditions[?(@.type=="WebhookReachable")].status`,description="Every webhook has its CA bundle and ready endpoints"
+// +kubebuilder:printcolumn:name="Degraded",type=string,JSONPath=`.status.conditions[?(@.type=="Degraded")].status`,description="A generated resource failed",priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="overcommit is a singleton, .metadata.name must be 'cluster'"
 
 // Overcommit is the Schema for the overcommits API
@@ -59,3 +310,56 @@ type OvercommitList struct {
 func init() {
 	SchemeBuilder.Register(&Overcommit{}, &OvercommitList{})
 }
+
+// Consolidated reports whether a single webhook deployment serves every OvercommitClass.
+func (in *Overcommit) Consolidated() bool {
+	return in.Spec.MutatingWebhook != nil && in.Spec.MutatingWebhook.Topology == WebhookTopologyConsolidated
+}
+
+// OvercommitLabels returns the labels selecting the class of pods and namespaces at the given
+// time, by precedence: the overcommitLabel, then the previous one while its migration is within
+// the grace period, or until the change of overcommitLabel is observed.
+func (in *Overcommit) OvercommitLabels(now time.Time) []string {
+	labels := []string{in.Spec.OvercommitLabel}
+	if migration := in.Status.LabelMigration; migration != nil && migration.Phase == LabelMigrationInProgress &&
+		migration.To == in.Spec.OvercommitLabel && migration.From != migration.To && now.Before(migration.Deadline.Time) {
+		return append(labels, migration.From)
+	}
+	if previous := in.Status.OvercommitLabel; previous != "" && previous != in.Spec.OvercommitLabel {
+		return append(labels, previous)
+	}
+	return labels
+}
+
+// LabelMigrationGracePeriod returns the grace period of a label migration.
+func (in *Overcommit) LabelMigrationGracePeriod() time.Duration {
+	if in.Spec.LabelMigration == nil || in.Spec.LabelMigration.GracePeriod == nil {
+		return DefaultLabelMigrationGracePeriod
+	}
+	return in.Spec.LabelMigration.GracePeriod.Duration
+}
+
+// CertificateProvider returns the provider of the serving certificates of the webhooks,
+// cert-manager when it isn't set.
+func (in *Overcommit) CertificateProvider() CertificateProvider {
+	if in.Spec.Certificates == nil || in.Spec.Certificates.Provider == "" {
+		return CertificateProviderCertManager
+	}
+	return in.Spec.Certificates.Provider
+}
+
+// CAValidity returns how long the CA of the builtin provider is valid and how long before it
+// expires it is rotated.
+func (in *Overcommit) CAValidity() (validity, renewBefore time.Duration) {
+	validity, renewBefore = DefaultCAValidity, DefaultCARenewBefore
+	if in.Spec.Certificates == nil || in.Spec.Certificates.Builtin == nil {
+		return validity, renewBefore
+	}
+	if builtin := in.Spec.Certificates.Builtin; builtin.CAValidity != nil {
+		validity = builtin.CAValidity.Duration
+	}
+	if builtin := in.Spec.Certificates.Builtin; builtin.CARenewBefore != nil {
+		renewBefore = builtin.CARenewBefore.Duration
+	}
+	return validity, renewBefore
+}
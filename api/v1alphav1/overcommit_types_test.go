@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Overcommit labels", func() {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var overcommitObject *Overcommit
+
+	BeforeEach(func() {
+		overcommitObject = &Overcommit{
+			Spec:   OvercommitSpec{OvercommitLabel: "inditex.dev/overcommit-class"},
+			Status: OvercommitStatus{OvercommitLabel: "inditex.dev/overcommit-class"},
+		}
+	})
+
+	It("Should honor only the overcommitLabel without a migration", func() {
+		Expect(overcommitObject.OvercommitLabels(now)).To(Equal([]string{"inditex.dev/overcommit-class"}))
+	})
+
+	It("Should honor the previous label until the change is observed", func() {
+		overcommitObject.Status.OvercommitLabel = "inditex.com/overcommit-class"
+		Expect(overcommitObject.OvercommitLabels(now)).To(Equal([]string{"inditex.dev/overcommit-class", "inditex.com/overcommit-class"}))
+	})
+
+	It("Should honor the previous label during the grace period only", func() {
+		overcommitObject.Status.LabelMigration = &LabelMigrationStatus{
+			From:      "inditex.com/overcommit-class",
+			To:        "inditex.dev/overcommit-class",
+			Phase:     LabelMigrationInProgress,
+			StartTime: metav1.NewTime(now.Add(-time.Hour)),
+			Deadline:  metav1.NewTime(now.Add(time.Hour)),
+		}
+		Expect(overcommitObject.OvercommitLabels(now)).To(Equal([]string{"inditex.dev/overcommit-class", "inditex.com/overcommit-class"}))
+		Expect(overcommitObject.OvercommitLabels(now.Add(2 * time.Hour))).To(Equal([]string{"inditex.dev/overcommit-class"}))
+
+		overcommitObject.Status.LabelMigration.Phase = LabelMigrationCompleted
+		Expect(overcommitObject.OvercommitLabels(now)).To(Equal([]string{"inditex.dev/overcommit-class"}))
+	})
+
+	It("Should default the grace period", func() {
+		Expect(overcommitObject.LabelMigrationGracePeriod()).To(Equal(DefaultLabelMigrationGracePeriod))
+		overcommitObject.Spec.LabelMigration = &LabelMigrationSpec{GracePeriod: &metav1.Duration{Duration: time.Hour}}
+		Expect(overcommitObject.LabelMigrationGracePeriod()).To(Equal(time.Hour))
+	})
+
+	It("Should default the certificate provider and the CA validity", func() {
+		Expect(overcommitObject.CertificateProvider()).To(Equal(CertificateProviderCertManager))
+		validity, renewBefore := overcommitObject.CAValidity()
+		Expect(validity).To(Equal(DefaultCAValidity))
+		Expect(renewBefore).To(Equal(DefaultCARenewBefore))
+
+		overcommitObject.Spec.Certificates = &CertificatesSpec{
+			Provider: CertificateProviderBuiltin,
+			Builtin:  &BuiltinCertificatesSpec{CARenewBefore: &metav1.Duration{Duration: time.Hour}},
+		}
+		Expect(overcommitObject.CertificateProvider()).To(Equal(CertificateProviderBuiltin))
+		validity, renewBefore = overcommitObject.CAValidity()
+		Expect(validity).To(Equal(DefaultCAValidity))
+		Expect(renewBefore).To(Equal(time.Hour))
+	})
+})
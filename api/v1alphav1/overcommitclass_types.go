@@ -6,6 +6,8 @@
 package v1alphav1
 
 import (
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -13,6 +15,148 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// BaselineMode selects the value of a container resource the overcommit ratio is applied to.
+// +kubebuilder:validation:Enum=fromLimits;fromRequests;max
+type BaselineMode string
+
+const (
+	// BaselineFromLimits applies the ratio to the limit of the resource. It is the default.
+	BaselineFromLimits BaselineMode = "fromLimits"
+	// BaselineFromRequests scales down the request already set on the container.
+	BaselineFromRequests BaselineMode = "fromRequests"
+	// BaselineMax applies the ratio to the highest of the limit and the request.
+	BaselineMax BaselineMode = "max"
+)
+
+// MemoryRounding is the boundary memory requests are rounded to.
+// +kubebuilder:validation:Enum=Mi;Gi
+type MemoryRounding string
+
+const (
+	// MemoryRoundingMi rounds memory requests to mebibytes.
+	MemoryRoundingMi MemoryRounding = "Mi"
+	// MemoryRoundingGi rounds memory requests to gibibytes.
+	MemoryRoundingGi MemoryRounding = "Gi"
+)
+
+// ExclusionsSpec defines the pods a class leaves untouched, on top of its selectors and match
+// conditions.
+type ExclusionsSpec struct {
+	// OwnerKinds are the kinds of the owners of the excluded pods, matched against the owner
+	// of the pod and its root owner, for example DaemonSet, StatefulSet, Job or CronJob. Node
+	// excludes the static pods mirrored by the kubelet.
+	// +listType=set
+	// +optional
+	OwnerKinds []string `json:"ownerKinds,omitempty"`
+	// PriorityClassNames are the priority classes of the excluded pods.
+	// +listType=set
+	// +optional
+	PriorityClassNames []string `json:"priorityClassNames,omitempty"`
+	// Guaranteed excludes the pods of the Guaranteed QoS class, whose requests are their
+	// limits.
+	// +optional
+	Guaranteed bool `json:"guaranteed,omitempty"`
+	// ServiceAccountOptOut also honors the opt-out annotation of the pods whose service account
+	// is allowed to opt them out, whoever creates them. Anyone who can create pods with that
+	// service account in the namespace can then opt them out. Only the user that creates the
+	// pod is authorized when it is not set.
+	// +optional
+	ServiceAccountOptOut bool `json:"serviceAccountOptOut,omitempty"`
+}
+
+// InitContainersSpec defines the overcommit of the run-to-completion init containers. Native
+// sidecars, the init containers with restartPolicy Always, run next to the containers and are
+// overcommitted like them.
+type InitContainersSpec struct {
+	// Skip leaves the run-to-completion init containers untouched.
+	// +optional
+	Skip bool `json:"skip,omitempty"`
+	// Ratios overrides, per resource, the overcommit ratios of the class for the
+	// run-to-completion init containers.
+	// +optional
+	Ratios map[corev1.ResourceName]float64 `json:"ratios,omitempty"`
+}
+
+// ContainerRule changes the overcommit of the containers, init containers and native sidecars
+// it matches. A rule that sets neither containerName nor image matches every container.
+type ContainerRule struct {
+	// Name identifies the rule in the logs of the decisions.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// ContainerName is a regular expression the whole name of the container must match.
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+	// Image is a glob the whole image of the container must match: * matches any sequence of
+	// characters, including /, and ? any single character.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Exclude leaves the matched containers untouched.
+	// +optional
+	Exclude bool `json:"exclude,omitempty"`
+	// Ratios overrides, per resource, the overcommit ratios of the class for the matched
+	// containers. Only cpu, memory and ephemeral-storage can be set, ephemeral-storage is
+	// overcommitted even without a ratio in the class.
+	// +optional
+	Ratios map[corev1.ResourceName]float64 `json:"ratios,omitempty"`
+	// MinRequest overrides, per resource, the minRequest of the class for the matched
+	// containers: the floor of their requests.
+	// +optional
+	MinRequest corev1.ResourceList `json:"minRequest,omitempty"`
+}
+
+// RoundingSpec defines how the calculated requests are rounded. Requests are rounded to
+// the nearest boundary, but never to zero and never above the limit.
+type RoundingSpec struct {
+	// CpuMillicores rounds the CPU requests to a multiple of this number of millicores.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CpuMillicores int64 `json:"cpuMillicores,omitempty"`
+	// Memory rounds the memory requests to Mi or Gi boundaries.
+	// +optional
+	Memory MemoryRounding `json:"memory,omitempty"`
+}
+
+// RequestPolicy defines what happens to the requests a container already sets.
+// +kubebuilder:validation:Enum=Override;OnlyLower;RespectExplicit
+type RequestPolicy string
+
+const (
+	// RequestPolicyOverride replaces the existing requests with the calculated ones.
+	RequestPolicyOverride RequestPolicy = "Override"
+	// RequestPolicyOnlyLower replaces the existing requests only when the calculated ones are lower.
+	RequestPolicyOnlyLower RequestPolicy = "OnlyLower"
+	// RequestPolicyRespectExplicit keeps the existing requests and only fills in the missing ones.
+	RequestPolicyRespectExplicit RequestPolicy = "RespectExplicit"
+)
+
+// Mode defines whether the overcommit of a class is applied to the pods.
+// +kubebuilder:validation:Enum=Enforce;Audit
+type Mode string
+
+const (
+	// ModeEnforce sets the calculated requests on the pods.
+	ModeEnforce Mode = "Enforce"
+	// ModeAudit leaves the requests untouched and only records the calculated ones in the
+	// annotations of the pod, an event and the audit metrics.
+	ModeAudit Mode = "Audit"
+)
+
+// OnError defines what happens to a pod when its overcommit can't be decided because the
+// Overcommit resource, the OvercommitClasses or its namespace can't be read.
+// +kubebuilder:validation:Enum=Deny;Admit;AdmitUnchanged
+type OnError string
+
+const (
+	// OnErrorDeny rejects the pod with the reason of the failure.
+	OnErrorDeny OnError = "Deny"
+	// OnErrorAdmit admits the pod with the overcommit decided from what could be read: a
+	// namespace that can't be read is ignored. Without the Overcommit resource or the
+	// OvercommitClasses the pod is admitted unchanged.
+	OnErrorAdmit OnError = "Admit"
+	// OnErrorAdmitUnchanged admits the pod without changing its requests.
+	OnErrorAdmitUnchanged OnError = "AdmitUnchanged"
+)
+
 // OvercommitClassSpec defines the desired state of OvercommitClass
 type OvercommitClassSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -26,25 +170,116 @@ type OvercommitClassSpec struct {
 	// +kubebuilder:validation:Maximum=1
 	// +kubebuilder:validation:Required
 	MemoryOvercommit float64 `json:"memoryOvercommit,omitempty"`
-	// +kubebuilder:validation:Required
+	// EphemeralStorageOvercommit is the ratio applied to ephemeral-storage. Not set means
+	// ephemeral-storage is not overcommitted. CPU, memory and ephemeral-storage are the only
+	// resources that can be overcommitted: Kubernetes requires the requests of hugepages and
+	// extended resources to be equal to their limits, so the class has no generic ratio map
+	// for arbitrary extended resources and the validating webhook rejects their ratios.
+	// +kubebuilder:validation:Minimum=0.0001
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	EphemeralStorageOvercommit float64 `json:"ephemeralStorageOvercommit,omitempty"`
+	// Baseline selects, per resource, the value the overcommit ratio is applied to.
+	// Resources that are not listed use fromLimits.
+	// +optional
+	Baseline map[corev1.ResourceName]BaselineMode `json:"baseline,omitempty"`
+	// MinRequest is the lowest request set on a container, per resource. It is never
+	// above the limit of the container.
+	// +optional
+	MinRequest corev1.ResourceList `json:"minRequest,omitempty"`
+	// MaxRequest is the highest request set on a container, per resource.
+	// +optional
+	MaxRequest corev1.ResourceList `json:"maxRequest,omitempty"`
+	// Rounding rounds the calculated requests.
+	// +optional
+	Rounding *RoundingSpec `json:"rounding,omitempty"`
+	// RequestPolicy defines what happens to the requests a container already sets. Pods can
+	// choose a stricter policy with the overcommit.inditex.dev/request-policy annotation.
+	// +kubebuilder:default=Override
+	// +optional
+	RequestPolicy RequestPolicy `json:"requestPolicy,omitempty"`
+	// Mode defines whether the calculated requests are set on the pods (Enforce) or only
+	// recorded to compare them against the real usage (Audit).
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode Mode `json:"mode,omitempty"`
+	// OnError defines what happens to the pods matched by the webhook of the class when
+	// their overcommit can't be decided. When the OvercommitClasses themselves can't be read,
+	// the webhook applies the policy it last read for the class, or Deny if it never read it.
+	// +kubebuilder:default=Admit
+	// +optional
+	OnError OnError `json:"onError,omitempty"`
+	// ExcludedNamespaces is a regex of the namespaces the class doesn't apply to.
+	// Deprecated: use namespaceSelector or excludedNamespaceNames.
+	// +optional
 	ExcludedNamespaces string `json:"excludedNamespaces,omitempty"`
+	// NamespaceSelector selects the namespaces of the pods the class applies to.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// ExcludedNamespaceNames are the namespaces the class doesn't apply to.
+	// +optional
+	ExcludedNamespaceNames []string `json:"excludedNamespaceNames,omitempty"`
+	// PodSelector selects the pods the class applies to, on top of the overcommit label.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// MatchConditions are CEL expressions the admission request must match for the class
+	// to apply. They are added to the match conditions of the generated
+	// MutatingWebhookConfiguration and use the same CEL environment. The API server allows 64
+	// match conditions per webhook and up to two are generated, hence at most 62.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=62
+	// +optional
+	MatchConditions []admissionv1.MatchCondition `json:"matchConditions,omitempty"`
+	// Exclusions are the pods the class leaves untouched.
+	// +optional
+	Exclusions *ExclusionsSpec `json:"exclusions,omitempty"`
+	// InitContainers defines the overcommit of the run-to-completion init containers, which
+	// only add to the request of the pod when they request more than its containers.
+	// +optional
+	InitContainers *InitContainersSpec `json:"initContainers,omitempty"`
+	// ContainerRules change the overcommit of some containers of the pods, such as sidecars.
+	// The first rule that matches a container applies to it.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	ContainerRules []ContainerRule `json:"containerRules,omitempty"`
 	// +kubebuilder:default=false
 	IsDefault   bool              `json:"isDefault,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// Deployment customizes the webhook Deployment of the class in the PerClass topology, over
+	// the deployment template of the Overcommit resource.
+	// +optional
+	Deployment *DeploymentTemplate `json:"deployment,omitempty"`
 }
 
+// ResourceStatus is the readiness of a resource generated by the operator
 type ResourceStatus struct {
-	Name  string `json:"name,omitempty"`
-	Ready bool   `json:"ready"`
+	Name string `json:"name,omitempty"`
+	// Kind of the resource: Deployment, Service, Certificate, Issuer or a webhook configuration
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Ready is true when the resource works, not only when it exists: a Deployment with every
+	// replica available and rolled out, an issued Certificate or a webhook configuration with the
+	// CA bundle injected and ready endpoints behind its Service
+	Ready bool `json:"ready"`
+	// Reason is a CamelCase reason of the readiness
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable explanation of the readiness
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // OvercommitClassStatus defines the observed state of OvercommitClass
 type OvercommitClassStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-	Resources  []ResourceStatus   `json:"resources,omitempty"`
-	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the class the status was computed for
+	// +optional
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Resources          []ResourceStatus   `json:"resources,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -53,6 +288,10 @@ type OvercommitClassStatus struct {
 // +kubebuilder:printcolumn:name="CPU",type=number,JSONPath=".spec.cpuOvercommit",description="CPU overcommit ratio"
 // +kubebuilder:printcolumn:name="Memory",type=number,JSONPath=".spec.memoryOvercommit",description="Memory overcommit ratio"
 // +kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=".spec.isDefault",description="Is default overcommit class"
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`,description="Every webhook and controller deployment is available"
+// +kubebuilder:printcolumn:name="Reachable",type=string,JSONPath=`.status.conditions[?(@.type=="WebhookReachable")].status`,description="Every webhook has its CA bundle and ready endpoints"
+// +kubebuilder:printcolumn:name="Degraded",type=string,JSONPath=`.status.conditions[?(@.type=="Degraded")].status`,description="A generated resource failed",priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 
 // OvercommitClass is the Schema for the overcommitclasses API
 type OvercommitClass struct {
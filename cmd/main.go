@@ -10,28 +10,41 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	overcommitv1 "github.com/InditexTech/k8s-overcommit-operator/api/v1"
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	occontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/overcommitclass"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/resources"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 
 	overcommitcontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/overcommit"
 	webhookcorev1mutating "github.com/InditexTech/k8s-overcommit-operator/internal/webhook/v1alphav1/mutating"
+	webhookovercommitclass "github.com/InditexTech/k8s-overcommit-operator/internal/webhook/v1alphav1/overcommitclass"
 	webhookcorev1validating "github.com/InditexTech/k8s-overcommit-operator/internal/webhook/v1alphav1/validating"
 	// +kubebuilder:scaffold:imports
 )
@@ -44,6 +57,8 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(overcommit.AddToScheme(scheme))
+	utilruntime.Must(overcommitv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(certmanagerv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
@@ -65,6 +80,7 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	configFlags := config.BindFlags(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 	}
@@ -73,6 +89,13 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	cfg, err := configFlags.Load()
+	if err != nil {
+		setupLog.Error(err, "unable to load the configuration")
+		os.Exit(1)
+	}
+	setupLog.Info("Loaded the configuration", "role", cfg.Role, "namespace", cfg.Namespace)
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -111,7 +134,7 @@ func main() {
 		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/metrics/filters#WithAuthenticationAndAuthorization
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
-	deploymentName, err := utils.GetPodDeploymentName()
+	deploymentName, err := utils.GetPodDeploymentName(cfg.Namespace, cfg.PodName)
 	if err != nil {
 		setupLog.Error(err, "unable to get pod deployment name")
 		os.Exit(1)
@@ -123,13 +146,36 @@ func main() {
 		HealthProbeBindAddress:  probeAddr,
 		LeaderElection:          enableLeaderElection,
 		LeaderElectionID:        deploymentName + ".inditex.dev",
-		LeaderElectionNamespace: os.Getenv("POD_NAMESPACE"),
+		LeaderElectionNamespace: cfg.Namespace,
+		// Only the OvercommitClass CRD is read, the other CRDs of the cluster are not cached
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&apiextensionsv1.CustomResourceDefinition{}: {
+					Field: fields.OneTermEqualSelector("metadata.name", resources.OvercommitClassCRDName),
+				},
+			},
+		},
 	}
-	// nolint:goconst
-	if os.Getenv("ENABLE_POD_MUTATING_WEBHOOK") == "true" || os.Getenv("ENABLE_POD_VALIDATING_WEBHOOK") == "true" || os.Getenv("ENABLE_OC_VALIDATING_WEBHOOK") == "true" {
+	if !cfg.Role.Webhook() {
+		// The controllers only own Deployments, Services and PodDisruptionBudgets in the namespace
+		// of the operator, the other namespaces are not cached. The webhooks read the owners of
+		// the pods in every namespace.
+		for _, object := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &policyv1.PodDisruptionBudget{}} {
+			mgrOptions.Cache.ByObject[object] = cache.ByObject{Namespaces: map[string]cache.Config{cfg.Namespace: {}}}
+		}
+	}
+	var certificateReloader *certificates.Reloader
+	if cfg.Role.Webhook() {
+		// The certificate is reloaded when its provider rotates it
+		reloader, err := certificates.NewReloader(cfg.CertDir, time.Minute)
+		if err != nil {
+			setupLog.Error(err, "unable to load the webhook certificate")
+			os.Exit(1)
+		}
+		certificateReloader = reloader
 		webhookServer := webhook.NewServer(webhook.Options{
-			TLSOpts: tlsOpts,
-			CertDir: os.Getenv("WEBHOOK_CERT_DIR"),
+			TLSOpts: append(tlsOpts, reloader.ConfigureTLS),
+			CertDir: cfg.CertDir,
 		})
 
 		mgrOptions.WebhookServer = webhookServer
@@ -140,89 +186,86 @@ func main() {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
-
-	if os.Getenv("ENABLE_OVERCOMMIT_CONTROLLER") == "true" {
-		// Get the image of the pod
-		ctx := context.Background()
-		registry, image, tag, err := utils.GetPodImageDetails(ctx, mgr.GetAPIReader())
-		if err != nil {
-			setupLog.Error(err, "unable to get pod image details")
+	if certificateReloader != nil {
+		if err := mgr.Add(certificateReloader); err != nil {
+			setupLog.Error(err, "unable to set up the webhook certificate reloader")
 			os.Exit(1)
 		}
+	}
 
-		serviceAccountName, err := utils.GetPodServiceAccount(mgr.GetAPIReader())
-		if err != nil {
-			setupLog.Error(err, "unable to get pod service account")
-			os.Exit(1)
-		}
-		err = os.Setenv("SERVICE_ACCOUNT_NAME", serviceAccountName)
+	// The generated deployments run the image of the operator unless it is configured, and every
+	// role reports the version of its own image
+	if cfg.Image == "" {
+		registry, image, tag, err := utils.GetPodImageDetails(context.Background(), mgr.GetAPIReader(), cfg.Namespace, cfg.PodName)
 		if err != nil {
-			setupLog.Error(err, "unable to set service account name")
-			os.Exit(1)
-		}
-		err = os.Setenv("IMAGE_REGISTRY", registry)
-		if err != nil {
-			setupLog.Error(err, "unable to set image registry")
-			os.Exit(1)
-		}
-		err = os.Setenv("IMAGE_REPOSITORY", image)
-		if err != nil {
-			setupLog.Error(err, "unable to set image repository")
+			setupLog.Error(err, "unable to get pod image details")
 			os.Exit(1)
 		}
-		err = os.Setenv("APP_VERSION", tag)
-		if err != nil {
-			setupLog.Error(err, "unable to set app version")
-			os.Exit(1)
+		cfg.Image = registry + "/" + image + ":" + tag
+	}
+
+	switch cfg.Role {
+	case config.RoleOperator:
+		// The generated deployments run the service account of the operator unless it is
+		// configured
+		if cfg.ServiceAccountName == "" {
+			serviceAccountName, err := utils.GetPodServiceAccount(mgr.GetAPIReader(), cfg.Namespace, cfg.PodName)
+			if err != nil {
+				setupLog.Error(err, "unable to get pod service account")
+				os.Exit(1)
+			}
+			cfg.ServiceAccountName = serviceAccountName
 		}
 
 		setupLog.Info("Enabling bootstrap controller")
 		if err = (&overcommitcontroller.OvercommitReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			Recorder:     mgr.GetEventRecorderFor("overcommit-controller"),
+			ResyncPeriod: cfg.ResyncPeriod.Duration,
+			APIReader:    mgr.GetAPIReader(),
+			Config:       cfg,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "Overcommit")
 			os.Exit(1)
 		}
-	}
 
-	// nolint:goconst
-	if os.Getenv("ENABLE_OVERCOMMIT_CLASS_CONTROLLER") == "true" {
+	case config.RoleClassController:
 		setupLog.Info("Enabling overcommit class controller")
 		// Register overcommitClass controller
 		if err = (&occontroller.OvercommitClassReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			Recorder:     mgr.GetEventRecorderFor("overcommitclass-controller"),
+			ResyncPeriod: cfg.ResyncPeriod.Duration,
+			APIReader:    mgr.GetAPIReader(),
+			Config:       cfg,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "OvercommitClass")
 			os.Exit(1)
 		}
-	}
 
-	// nolint:goconst
-	if os.Getenv("ENABLE_POD_MUTATING_WEBHOOK") == "true" {
+	case config.RoleMutatingWebhook:
 		setupLog.Info("Enabling pod mutating webhook")
 		// Register pod mutating webhook
-		if err = webhookcorev1mutating.SetupPodWebhookWithManager(mgr); err != nil {
+		if err = webhookcorev1mutating.SetupPodWebhookWithManager(mgr, cfg); err != nil {
 			setupLog.Error(err, "unable to create mutating webhook", "webhook", "Pod")
 			os.Exit(1)
 		}
-	}
-	// nolint:goconst
-	if os.Getenv("ENABLE_POD_VALIDATING_WEBHOOK") == "true" {
+
+	case config.RoleValidatingWebhook:
 		setupLog.Info("Enabling pod validating webhook")
 		// Register pod validating webhook
 		if err = webhookcorev1validating.SetupPodWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create validating webhook", "webhook", "Pod")
 			os.Exit(1)
 		}
-	}
 
-	// nolint:goconst
-	if os.Getenv("ENABLE_OC_VALIDATING_WEBHOOK") == "true" {
+	case config.RoleClassWebhook:
 		setupLog.Info("Enabling overcommitClass validating webhook")
-		// Register overcommitClass validation webhook
-		if err = (&overcommit.OvercommitClass{}).SetupWebhookWithManager(mgr); err != nil {
+		// Register overcommitClass validation webhook, which also serves the
+		// conversion between the OvercommitClass versions
+		if err = webhookovercommitclass.SetupOvercommitClassWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "OvercommitClass")
 			os.Exit(1)
 		}
@@ -237,7 +280,7 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
-	metrics.K8sOvercommitOperatorVersion.WithLabelValues(os.Getenv("APP_VERSION")).Set(1)
+	metrics.K8sOvercommitOperatorVersion.WithLabelValues(cfg.Version()).Set(1)
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
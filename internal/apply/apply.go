@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package apply reconciles the resources generated by the operator to their desired state with
+// server-side apply, and reports the changes other managers made to the fields the operator owns.
+package apply
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/events"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// FieldManager is the field manager of the fields the operator applies.
+const FieldManager = "k8s-overcommit-operator"
+
+// legacyFieldManager is the field manager of the updates of the operator before it used
+// server-side apply. Taking its fields over is not a drift.
+const legacyFieldManager = "manager"
+
+// Applier applies the generated resources on behalf of their owner.
+type Applier struct {
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// Apply makes the owner the controller of the object and applies it with the operator field
+// manager, forcing the ownership of its fields: any field of the object changed by someone else
+// is set back, while the fields the object doesn't set, like the CA bundles injected by
+// cert-manager, are kept. The object is updated with the result.
+func (a Applier) Apply(ctx context.Context, owner client.Object, object client.Object) error {
+	if err := controllerutil.SetControllerReference(owner, object, a.Scheme); err != nil {
+		return err
+	}
+	gvk, err := apiutil.GVKForObject(object, a.Scheme)
+	if err != nil {
+		return err
+	}
+	object.GetObjectKind().SetGroupVersionKind(gvk)
+
+	current := object.DeepCopyObject().(client.Object)
+	err = a.Client.Get(ctx, client.ObjectKeyFromObject(object), current)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("error getting %s %s: %w", gvk.Kind, object.GetName(), err)
+	}
+	exists := err == nil
+
+	object.SetManagedFields(nil)
+	object.SetResourceVersion("")
+	if err := a.Client.Patch(ctx, object, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("error applying %s %s: %w", gvk.Kind, object.GetName(), err)
+	}
+
+	if !exists {
+		events.Created(a.Recorder, owner, object, controllerutil.OperationResultCreated)
+		return nil
+	}
+	for _, manager := range OverriddenManagers(current.GetManagedFields(), object.GetManagedFields()) {
+		metrics.K8sOvercommitOperatorDriftCorrectionsTotal.WithLabelValues(gvk.Kind, manager).Inc()
+		events.DriftCorrected(a.Recorder, owner, gvk.Kind, object.GetName(), manager)
+	}
+	return nil
+}
+
+// OverriddenManagers returns the managers, other than the operator, that lost fields between
+// the managed fields before and after an apply: the fields they changed were set back.
+func OverriddenManagers(before, after []metav1.ManagedFieldsEntry) []string {
+	managers := []string{}
+	for _, entry := range before {
+		if entry.Manager == FieldManager || entry.Manager == legacyFieldManager || entry.Subresource != "" {
+			continue
+		}
+		if !kept(entry, after) {
+			managers = append(managers, entry.Manager)
+		}
+	}
+	return managers
+}
+
+// kept reports whether the entry manages the same fields after the apply.
+func kept(entry metav1.ManagedFieldsEntry, after []metav1.ManagedFieldsEntry) bool {
+	for _, other := range after {
+		if other.Manager != entry.Manager || other.Operation != entry.Operation || other.Subresource != entry.Subresource {
+			continue
+		}
+		if entry.FieldsV1 == nil || other.FieldsV1 == nil {
+			return entry.FieldsV1 == other.FieldsV1
+		}
+		return bytes.Equal(entry.FieldsV1.Raw, other.FieldsV1.Raw)
+	}
+	return false
+}
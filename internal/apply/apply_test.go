@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func entry(manager string, operation metav1.ManagedFieldsOperationType, subresource, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   operation,
+		Subresource: subresource,
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestOverriddenManagers(t *testing.T) {
+	before := []metav1.ManagedFieldsEntry{
+		entry(FieldManager, metav1.ManagedFieldsOperationApply, "", `{"f:spec":{"f:replicas":{}}}`),
+		entry("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", `{"f:spec":{"f:template":{"f:spec":{"f:containers":{}}}}}`),
+		entry("cainjector", metav1.ManagedFieldsOperationUpdate, "", `{"f:webhooks":{}}`),
+		entry("kube-controller-manager", metav1.ManagedFieldsOperationUpdate, "status", `{"f:status":{}}`),
+		entry(legacyFieldManager, metav1.ManagedFieldsOperationUpdate, "", `{"f:spec":{"f:selector":{}}}`),
+	}
+
+	tests := []struct {
+		name     string
+		after    []metav1.ManagedFieldsEntry
+		expected []string
+	}{
+		{
+			name:     "nothing changed",
+			after:    before,
+			expected: []string{},
+		},
+		{
+			name: "fields taken back from a manager",
+			after: []metav1.ManagedFieldsEntry{
+				entry(FieldManager, metav1.ManagedFieldsOperationApply, "", `{"f:spec":{"f:replicas":{},"f:template":{}}}`),
+				entry("cainjector", metav1.ManagedFieldsOperationUpdate, "", `{"f:webhooks":{}}`),
+				entry("kube-controller-manager", metav1.ManagedFieldsOperationUpdate, "status", `{"f:status":{"f:replicas":{}}}`),
+			},
+			expected: []string{"kubectl-edit"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if managers := OverriddenManagers(before, test.after); !reflect.DeepEqual(managers, test.expected) {
+				t.Errorf("Expected the overridden managers %v, got %v", test.expected, managers)
+			}
+		})
+	}
+}
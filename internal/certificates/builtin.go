@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/events"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CASecretName is the secret of the CA of the builtin provider. Its ca.crt holds the CA bundle:
+// the current CA and, after a rotation, the previous one until it expires.
+const CASecretName = "k8s-overcommit-ca"
+
+// Validity and renewal of the serving certificates when their Certificate doesn't set them.
+const (
+	defaultValidity    = 365 * 24 * time.Hour
+	defaultRenewBefore = 30 * 24 * time.Hour
+)
+
+// errCANotFound is returned while the CA is not generated yet.
+var errCANotFound = errors.New("the CA of the builtin certificate provider is not generated yet")
+
+// Builtin issues the certificates with a CA generated by the operator, rotates the CA and the
+// certificates before they expire and injects the CA bundle.
+type Builtin struct {
+	Options
+	// Owner of the CA secret.
+	Owner         client.Object
+	CAValidity    time.Duration
+	CARenewBefore time.Duration
+	// Now returns the current time, time.Now when it isn't set.
+	Now func() time.Time
+}
+
+// Prepare generates the CA, or rotates it when it expires within CARenewBefore, and deletes the
+// Issuer of cert-manager.
+func (p *Builtin) Prepare(ctx context.Context) error {
+	if err := deleteCertManagerObject(ctx, p.Client, resources.GenerateIssuer(p.Namespace)); err != nil {
+		return err
+	}
+
+	now := p.now()
+	secret, err := p.getSecret(ctx, CASecretName)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	current := keyPair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+	if err == nil && !caNeedsRotation(current, now, p.CARenewBefore) {
+		// Drop the previous CA from the bundle once it expires
+		if caBundle := bundle(now, current.Cert, secret.Data["ca.crt"]); !bytes.Equal(caBundle, secret.Data["ca.crt"]) {
+			secret.Data["ca.crt"] = caBundle
+			if err := p.Client.Update(ctx, secret); err != nil {
+				return fmt.Errorf("error updating the CA bundle: %w", err)
+			}
+		}
+		return nil
+	}
+
+	ca, err := newCA(now, p.CAValidity)
+	if err != nil {
+		return fmt.Errorf("error generating the CA: %w", err)
+	}
+	data := map[string][]byte{
+		corev1.TLSCertKey:       ca.Cert,
+		corev1.TLSPrivateKeyKey: ca.Key,
+		"ca.crt":                bundle(now, ca.Cert, secret.Data["ca.crt"]),
+	}
+	if err := p.writeSecret(ctx, p.Owner, CASecretName, secret, data); err != nil {
+		return err
+	}
+	logf.FromContext(ctx).Info("Generated the CA of the builtin certificate provider", "secret", CASecretName)
+	if secret.ResourceVersion != "" {
+		events.CertificateReissued(p.Recorder, p.Owner, CASecretName, "the CA expires soon")
+	}
+	return nil
+}
+
+// Issue signs a certificate for the DNS names of the certificate with the CA into its secret,
+// unless the secret holds one signed by the current CA that doesn't expire within its renewal.
+func (p *Builtin) Issue(ctx context.Context, owner client.Object, certificate *certmanagerv1.Certificate) error {
+	ca, err := p.getSecret(ctx, CASecretName)
+	if apierrors.IsNotFound(err) {
+		return errCANotFound
+	}
+	if err != nil {
+		return err
+	}
+	caPair := keyPair{Cert: ca.Data[corev1.TLSCertKey], Key: ca.Data[corev1.TLSPrivateKeyKey]}
+
+	now := p.now()
+	validity, renewBefore := defaultValidity, defaultRenewBefore
+	if certificate.Spec.Duration != nil {
+		validity = certificate.Spec.Duration.Duration
+	}
+	if certificate.Spec.RenewBefore != nil {
+		renewBefore = certificate.Spec.RenewBefore.Duration
+	}
+
+	secretName := certificate.Spec.SecretName
+	secret, err := p.getSecret(ctx, secretName)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	serving := keyPair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+	reason := "the secret doesn't exist"
+	if err == nil {
+		reason = needsRenewal(serving, caPair.Cert, certificate.Spec.DNSNames, now, renewBefore)
+		if reason == "" {
+			return nil
+		}
+	}
+
+	if err := deleteCertManagerObject(ctx, p.Client, certificate.DeepCopy()); err != nil {
+		return err
+	}
+	serving, err = newServing(caPair, certificate.Spec.DNSNames, now, validity)
+	if err != nil {
+		return fmt.Errorf("error issuing the certificate of %s: %w", secretName, err)
+	}
+	data := map[string][]byte{
+		corev1.TLSCertKey:       serving.Cert,
+		corev1.TLSPrivateKeyKey: serving.Key,
+		"ca.crt":                ca.Data["ca.crt"],
+	}
+	if err := p.writeSecret(ctx, owner, secretName, secret, data); err != nil {
+		return err
+	}
+	if secret.ResourceVersion != "" {
+		events.CertificateReissued(p.Recorder, owner, secretName, reason)
+	}
+	return nil
+}
+
+// CABundle returns the CA bundle of the CA secret.
+func (p *Builtin) CABundle(ctx context.Context) ([]byte, error) {
+	secret, err := p.getSecret(ctx, CASecretName)
+	if apierrors.IsNotFound(err) {
+		return nil, errCANotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data["ca.crt"], nil
+}
+
+// Annotate does nothing, the operator injects the CA bundle.
+func (p *Builtin) Annotate(client.Object, *certmanagerv1.Certificate) {}
+
+// Authority returns the readiness of the CA secret.
+func (p *Builtin) Authority(ctx context.Context) []Status {
+	return []Status{p.checkSecret(ctx, CASecretName)}
+}
+
+// Check returns the readiness of the secret of the certificate.
+func (p *Builtin) Check(ctx context.Context, certificate *certmanagerv1.Certificate) Status {
+	return p.checkSecret(ctx, certificate.Spec.SecretName)
+}
+
+func (p *Builtin) checkSecret(ctx context.Context, name string) Status {
+	return Status{
+		Kind:   "Secret",
+		Name:   name,
+		Result: readiness.Check(ctx, p.Reader, "Secret", p.secretKey(name), &corev1.Secret{}, readiness.Secret),
+	}
+}
+
+// getSecret reads the secret from the API server. The secret is empty when it doesn't exist.
+func (p *Builtin) getSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := p.Reader.Get(ctx, p.secretKey(name), secret)
+	if client.IgnoreNotFound(err) != nil {
+		return secret, fmt.Errorf("error getting the secret %s: %w", name, err)
+	}
+	return secret, err
+}
+
+// writeSecret creates the secret or updates the current one with the data.
+func (p *Builtin) writeSecret(ctx context.Context, owner client.Object, name string, current *corev1.Secret, data map[string][]byte) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       p.Namespace,
+			ResourceVersion: current.ResourceVersion,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+	if err := controllerutil.SetControllerReference(owner, secret, p.Scheme); err != nil {
+		return err
+	}
+
+	if current.ResourceVersion == "" {
+		if err := p.Client.Create(ctx, secret); err != nil {
+			return fmt.Errorf("error creating the secret %s: %w", name, err)
+		}
+		events.Created(p.Recorder, owner, secret, controllerutil.OperationResultCreated)
+		return nil
+	}
+	// A secret of another type can't be updated to kubernetes.io/tls
+	if current.Type != corev1.SecretTypeTLS {
+		if err := p.Client.Delete(ctx, current); err != nil {
+			return fmt.Errorf("error replacing the secret %s: %w", name, err)
+		}
+		secret.ResourceVersion = ""
+		if err := p.Client.Create(ctx, secret); err != nil {
+			return fmt.Errorf("error replacing the secret %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := p.Client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("error updating the secret %s: %w", name, err)
+	}
+	return nil
+}
+
+func (p *Builtin) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// caNeedsRotation tells whether the CA can't be read or expires within renewBefore.
+func caNeedsRotation(ca keyPair, now time.Time, renewBefore time.Duration) bool {
+	certificates, err := parseCertificates(ca.Cert)
+	if err != nil || len(ca.Key) == 0 {
+		return true
+	}
+	return !now.Add(renewBefore).Before(certificates[0].NotAfter)
+}
+
+func (o Options) secretKey(name string) client.ObjectKey {
+	return client.ObjectKey{Name: name, Namespace: o.Namespace}
+}
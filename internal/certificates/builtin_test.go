@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newBuiltin(t *testing.T, now *time.Time) (*Builtin, client.Client, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, certmanagerv1.AddToScheme, overcommit.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	recorder := record.NewFakeRecorder(10)
+	owner := &overcommit.Overcommit{ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "overcommit"}}
+	return &Builtin{
+		Options:       Options{Namespace: "k8s-overcommit", Client: c, Reader: c, Scheme: scheme, Recorder: recorder},
+		Owner:         owner,
+		CAValidity:    30 * 24 * time.Hour,
+		CARenewBefore: 24 * time.Hour,
+		Now:           func() time.Time { return *now },
+	}, c, recorder
+}
+
+func servingCertificate(dnsNames ...string) *certmanagerv1.Certificate {
+	return &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "high-overcommit-webhook-certificate", Namespace: "k8s-overcommit"},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName:  "high-overcommit-webhook-secret",
+			DNSNames:    dnsNames,
+			Duration:    &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			RenewBefore: &metav1.Duration{Duration: 24 * time.Hour},
+		},
+	}
+}
+
+func TestBuiltinIssue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	provider, c, recorder := newBuiltin(t, &now)
+	owner := &overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: "high", UID: "high"}}
+
+	if err := provider.Issue(ctx, owner, servingCertificate(dnsNames...)); err != errCANotFound {
+		t.Fatalf("Expected the certificate not to be issued without CA, got %v", err)
+	}
+	if err := provider.Prepare(ctx); err != nil {
+		t.Fatal(err)
+	}
+	caBundle, err := provider.CABundle(ctx)
+	if err != nil || len(caBundle) == 0 {
+		t.Fatalf("Expected the CA bundle after the CA is generated, got %v", err)
+	}
+
+	if err := provider.Issue(ctx, owner, servingCertificate(dnsNames...)); err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "high-overcommit-webhook-secret", Namespace: "k8s-overcommit"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if secret.Type != corev1.SecretTypeTLS || secret.OwnerReferences[0].Name != "high" {
+		t.Errorf("Expected a TLS secret owned by the class, got %+v", secret.ObjectMeta)
+	}
+	if status := provider.Check(ctx, servingCertificate(dnsNames...)); !status.Ready {
+		t.Errorf("Expected the issued certificate to be ready, got %+v", status)
+	}
+	issued := secret.Data[corev1.TLSCertKey]
+	if got := <-recorder.Events; got != "Normal Created Created Secret k8s-overcommit-ca" {
+		t.Errorf("Unexpected event %q", got)
+	}
+	if got := <-recorder.Events; got != "Normal Created Created Secret high-overcommit-webhook-secret" {
+		t.Errorf("Unexpected event %q", got)
+	}
+
+	// A certificate that is still good is kept
+	if err := provider.Issue(ctx, owner, servingCertificate(dnsNames...)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(secret.Data[corev1.TLSCertKey], issued) {
+		t.Error("Expected the certificate not to be issued again")
+	}
+
+	// It is issued again when the DNS names change
+	if err := provider.Issue(ctx, owner, servingCertificate(append(dnsNames, "high-overcommit-webhook")...)); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-recorder.Events; got != "Normal CertificateRotated Issued a new certificate in secret high-overcommit-webhook-secret: the certificate doesn't cover high-overcommit-webhook" {
+		t.Errorf("Unexpected event %q", got)
+	}
+
+	// And before it expires
+	now = now.Add(6*24*time.Hour + time.Hour)
+	if err := provider.Issue(ctx, owner, servingCertificate(append(dnsNames, "high-overcommit-webhook")...)); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-recorder.Events; got != "Normal CertificateRotated Issued a new certificate in secret high-overcommit-webhook-secret: the certificate expires soon" {
+		t.Errorf("Unexpected event %q", got)
+	}
+}
+
+func TestBuiltinCARotation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	provider, _, recorder := newBuiltin(t, &now)
+
+	if err := provider.Prepare(ctx); err != nil {
+		t.Fatal(err)
+	}
+	<-recorder.Events
+	first, err := provider.CABundle(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The CA is rotated before it expires, the bundle keeps the previous CA
+	now = now.Add(29*24*time.Hour + time.Hour)
+	if err := provider.Prepare(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-recorder.Events; got != "Normal CertificateRotated Issued a new certificate in secret k8s-overcommit-ca: the CA expires soon" {
+		t.Errorf("Unexpected event %q", got)
+	}
+	rotated, err := provider.CABundle(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if certificates, _ := parseCertificates(rotated); len(certificates) != 2 || !bytes.Contains(rotated, first) {
+		t.Errorf("Expected the bundle to hold the previous and the new CA, got %d certificates", len(certificates))
+	}
+
+	// The previous CA is dropped once it expires
+	now = now.Add(2 * 24 * time.Hour)
+	if err := provider.Prepare(ctx); err != nil {
+		t.Fatal(err)
+	}
+	dropped, err := provider.CABundle(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if certificates, _ := parseCertificates(dropped); len(certificates) != 1 || bytes.Contains(dropped, first) {
+		t.Errorf("Expected the bundle to hold only the new CA, got %d certificates", len(certificates))
+	}
+}
+
+func TestInjectCABundle(t *testing.T) {
+	configuration := &admissionv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{InjectCAAnnotation: "k8s-overcommit/high-overcommit-webhook-certificate"}},
+		Webhooks:   []admissionv1.MutatingWebhook{{Name: "pod.overcommit.inditex.dev"}},
+	}
+
+	InjectCABundle(configuration, nil)
+	if _, ok := configuration.Annotations[InjectCAAnnotation]; !ok {
+		t.Error("Expected cert-manager to keep injecting the CA bundle without bundle")
+	}
+
+	InjectCABundle(configuration, []byte("bundle"))
+	if _, ok := configuration.Annotations[InjectCAAnnotation]; ok {
+		t.Error("Expected the injection by cert-manager to be removed")
+	}
+	if string(configuration.Webhooks[0].ClientConfig.CABundle) != "bundle" {
+		t.Errorf("Expected the CA bundle to be set, got %q", configuration.Webhooks[0].ClientConfig.CABundle)
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certificates issues the serving certificates of the webhooks generated by the operator
+// and injects the CA bundle that verifies them, with cert-manager, with a CA generated by the
+// operator, with certificates provided by the user or with the service CA of OpenShift.
+package certificates
+
+import (
+	"context"
+	"fmt"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InjectCAAnnotation makes cert-manager inject the CA bundle of a Certificate.
+const InjectCAAnnotation = "cert-manager.io/inject-ca-from"
+
+// Provider issues the serving certificates of the webhooks. The generated cert-manager
+// Certificates describe them: their secret, DNS names, duration and renewal.
+type Provider interface {
+	// Prepare sets up what the certificates are issued with.
+	Prepare(ctx context.Context) error
+	// Issue makes the secret of the certificate hold a serving certificate, owned by owner.
+	Issue(ctx context.Context, owner client.Object, certificate *certmanagerv1.Certificate) error
+	// CABundle returns the CA bundle to inject in the webhook configurations and the
+	// conversion, nil when cert-manager or the service CA inject it.
+	CABundle(ctx context.Context) ([]byte, error)
+	// Annotate sets the annotations the provider needs on the Service that serves the
+	// certificate, and on the webhook configurations and the CRDs that call it.
+	Annotate(object client.Object, certificate *certmanagerv1.Certificate)
+	// Authority returns the readiness of what the certificates are issued with.
+	Authority(ctx context.Context) []Status
+	// Check returns the readiness of the certificate.
+	Check(ctx context.Context, certificate *certmanagerv1.Certificate) Status
+}
+
+// Status is the readiness of a resource of a provider.
+type Status struct {
+	Kind string
+	Name string
+	readiness.Result
+}
+
+// Options are the clients the providers work with and the namespace of the operator, where the
+// secrets are.
+type Options struct {
+	Namespace string
+	Client    client.Client
+	// Reader reads the secrets, uncached so that the secrets of the cluster aren't watched.
+	Reader   client.Reader
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// New returns the provider of the certificates of the Overcommit resource.
+func New(overcommitObject *overcommit.Overcommit, options Options) Provider {
+	if options.Reader == nil {
+		options.Reader = options.Client
+	}
+	switch overcommitObject.CertificateProvider() {
+	case overcommit.CertificateProviderBuiltin:
+		validity, renewBefore := overcommitObject.CAValidity()
+		return &Builtin{Options: options, Owner: overcommitObject, CAValidity: validity, CARenewBefore: renewBefore}
+	case overcommit.CertificateProviderExternal:
+		external := &External{Options: options}
+		if overcommitObject.Spec.Certificates.External != nil {
+			external.Bundle = overcommitObject.Spec.Certificates.External.CABundle
+		}
+		return external
+	case overcommit.CertificateProviderServiceCA:
+		return &ServiceCA{Options: options}
+	default:
+		return &CertManager{Options: options, Owner: overcommitObject}
+	}
+}
+
+// InjectCABundle sets the CA bundle on every webhook of the configuration, replacing the
+// injection by cert-manager. It does nothing without a bundle.
+func InjectCABundle(object client.Object, bundle []byte) {
+	if bundle == nil {
+		return
+	}
+	annotations := object.GetAnnotations()
+	delete(annotations, InjectCAAnnotation)
+	object.SetAnnotations(annotations)
+
+	switch configuration := object.(type) {
+	case *admissionv1.MutatingWebhookConfiguration:
+		for i := range configuration.Webhooks {
+			configuration.Webhooks[i].ClientConfig.CABundle = bundle
+		}
+	case *admissionv1.ValidatingWebhookConfiguration:
+		for i := range configuration.Webhooks {
+			configuration.Webhooks[i].ClientConfig.CABundle = bundle
+		}
+	}
+}
+
+// CAInjectionAnnotations are the annotations that make cert-manager or the service CA inject
+// their CA bundle. The ones a provider doesn't set are removed from the CRDs.
+var CAInjectionAnnotations = []string{InjectCAAnnotation, InjectCABundleAnnotation}
+
+// deleteCertManagerObject deletes an Issuer or a Certificate so that cert-manager stops writing
+// the secrets of another provider. It succeeds when cert-manager isn't installed.
+func deleteCertManagerObject(ctx context.Context, c client.Client, object client.Object) error {
+	if err := IgnoreNotInstalled(c.Delete(ctx, object)); err != nil {
+		return fmt.Errorf("error deleting the cert-manager %T %s: %w", object, object.GetName(), err)
+	}
+	return nil
+}
+
+// IgnoreNotInstalled returns nil when the error is a not found error or cert-manager isn't
+// installed, the error otherwise.
+func IgnoreNotInstalled(err error) error {
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	return err
+}
+
+// CertManagerInstalled tells whether the cert-manager CRDs are installed, so that its resources
+// can be watched.
+func CertManagerInstalled(mapper meta.RESTMapper) bool {
+	_, err := mapper.RESTMapping(certmanagerv1.SchemeGroupVersion.WithKind("Certificate").GroupKind(), certmanagerv1.SchemeGroupVersion.Version)
+	return err == nil
+}
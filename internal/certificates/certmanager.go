@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"context"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/apply"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CertManager issues the certificates with the self-signed Issuer of the operator. cert-manager
+// rotates them and injects the CA bundle.
+type CertManager struct {
+	Options
+	// Owner of the Issuer.
+	Owner client.Object
+}
+
+// Prepare applies the Issuer.
+func (p *CertManager) Prepare(ctx context.Context) error {
+	return p.applier().Apply(ctx, p.Owner, resources.GenerateIssuer(p.Namespace))
+}
+
+// Issue applies the Certificate.
+func (p *CertManager) Issue(ctx context.Context, owner client.Object, certificate *certmanagerv1.Certificate) error {
+	return p.applier().Apply(ctx, owner, certificate)
+}
+
+// CABundle returns nil, cert-manager injects the CA bundle.
+func (p *CertManager) CABundle(context.Context) ([]byte, error) {
+	return nil, nil
+}
+
+// Annotate does nothing, the generated resources already request the injection of cert-manager.
+func (p *CertManager) Annotate(client.Object, *certmanagerv1.Certificate) {}
+
+// Authority returns the readiness of the Issuer.
+func (p *CertManager) Authority(ctx context.Context) []Status {
+	issuer := resources.GenerateIssuer(p.Namespace)
+	return []Status{{
+		Kind:   "Issuer",
+		Name:   issuer.Name,
+		Result: readiness.Check(ctx, p.Client, "Issuer", client.ObjectKeyFromObject(issuer), &certmanagerv1.Issuer{}, readiness.Issuer),
+	}}
+}
+
+// Check returns the readiness of the Certificate.
+func (p *CertManager) Check(ctx context.Context, certificate *certmanagerv1.Certificate) Status {
+	return Status{
+		Kind:   "Certificate",
+		Name:   certificate.Name,
+		Result: readiness.Check(ctx, p.Client, "Certificate", client.ObjectKeyFromObject(certificate), &certmanagerv1.Certificate{}, readiness.Certificate),
+	}
+}
+
+func (p *CertManager) applier() apply.Applier {
+	return apply.Applier{Client: p.Client, Scheme: p.Scheme, Recorder: p.Recorder}
+}
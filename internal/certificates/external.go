@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// External uses the certificates of the secrets created by the user, verified by the CA bundle
+// of the Overcommit resource. The user rotates them.
+type External struct {
+	Options
+	Bundle []byte
+}
+
+// Prepare deletes the Issuer of cert-manager.
+func (p *External) Prepare(ctx context.Context) error {
+	return deleteCertManagerObject(ctx, p.Client, resources.GenerateIssuer(p.Namespace))
+}
+
+// Issue checks that the secret of the certificate holds a certificate and its key, and deletes
+// the Certificate so that cert-manager doesn't overwrite it.
+func (p *External) Issue(ctx context.Context, _ client.Object, certificate *certmanagerv1.Certificate) error {
+	if err := deleteCertManagerObject(ctx, p.Client, certificate.DeepCopy()); err != nil {
+		return err
+	}
+	status := p.Check(ctx, certificate)
+	if !status.Ready {
+		return fmt.Errorf("error reading the secret %s of the external certificate provider: %s", status.Name, status.Message)
+	}
+	return nil
+}
+
+// CABundle returns the CA bundle of the Overcommit resource.
+func (p *External) CABundle(context.Context) ([]byte, error) {
+	return p.Bundle, nil
+}
+
+// Annotate does nothing, the operator injects the CA bundle.
+func (p *External) Annotate(client.Object, *certmanagerv1.Certificate) {}
+
+// Authority returns nothing, the certificates are issued outside the cluster.
+func (p *External) Authority(context.Context) []Status {
+	return nil
+}
+
+// Check returns the readiness of the secret of the certificate.
+func (p *External) Check(ctx context.Context, certificate *certmanagerv1.Certificate) Status {
+	return Status{
+		Kind:   "Secret",
+		Name:   certificate.Spec.SecretName,
+		Result: readiness.Check(ctx, p.Reader, "Secret", p.secretKey(certificate.Spec.SecretName), &corev1.Secret{}, readiness.Secret),
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// clockSkew backdates the certificates so that they are valid on nodes with a clock behind.
+const clockSkew = 5 * time.Minute
+
+// keyPair is a PEM encoded certificate and its private key.
+type keyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// newCA generates a self-signed CA valid for validity.
+func newCA(now time.Time, validity time.Duration) (keyPair, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "k8s-overcommit-ca", Organization: []string{"k8s-overcommit-operator"}},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return sign(template, nil)
+}
+
+// newServing generates a serving certificate for the DNS names signed by the CA, valid for
+// validity but never after the CA.
+func newServing(ca keyPair, dnsNames []string, now time.Time, validity time.Duration) (keyPair, error) {
+	parent, err := tls.X509KeyPair(ca.Cert, ca.Key)
+	if err != nil {
+		return keyPair{}, fmt.Errorf("error reading the CA: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(parent.Certificate[0])
+	if err != nil {
+		return keyPair{}, fmt.Errorf("error reading the CA: %w", err)
+	}
+	parent.Leaf = caCert
+
+	notAfter := now.Add(validity)
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+	commonName := ""
+	if len(dnsNames) > 0 {
+		commonName = dnsNames[0]
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return sign(template, &parent)
+}
+
+// sign generates a key and a certificate of the template, signed by the parent or self-signed
+// without parent.
+func sign(template *x509.Certificate, parent *tls.Certificate) (keyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return keyPair{}, fmt.Errorf("error generating the private key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return keyPair{}, fmt.Errorf("error generating the serial number: %w", err)
+	}
+	template.SerialNumber = serial
+
+	issuer, signer := template, any(key)
+	if parent != nil {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		return keyPair{}, fmt.Errorf("error signing the certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return keyPair{}, fmt.Errorf("error encoding the private key: %w", err)
+	}
+	return keyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// parseCertificates parses the PEM encoded certificates of the bundle.
+func parseCertificates(bundle []byte) ([]*x509.Certificate, error) {
+	certificates := []*x509.Certificate{}
+	for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certificates, nil
+}
+
+// bundle returns the PEM encoded CA certificates that aren't expired at now, without duplicates.
+func bundle(now time.Time, certificates ...[]byte) []byte {
+	var result bytes.Buffer
+	seen := [][]byte{}
+	for _, pemBytes := range certificates {
+		parsed, err := parseCertificates(pemBytes)
+		if err != nil {
+			continue
+		}
+		for _, certificate := range parsed {
+			if now.After(certificate.NotAfter) || slices.ContainsFunc(seen, func(raw []byte) bool { return bytes.Equal(raw, certificate.Raw) }) {
+				continue
+			}
+			seen = append(seen, certificate.Raw)
+			_ = pem.Encode(&result, &pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+		}
+	}
+	return result.Bytes()
+}
+
+// needsRenewal tells why the serving certificate has to be issued again: it can't be read, it
+// isn't signed by the CA, it doesn't cover the DNS names or it expires within renewBefore. It
+// returns an empty string when the certificate is still good.
+func needsRenewal(serving keyPair, ca []byte, dnsNames []string, now time.Time, renewBefore time.Duration) string {
+	if _, err := tls.X509KeyPair(serving.Cert, serving.Key); err != nil {
+		return "the certificate can't be read"
+	}
+	certificates, err := parseCertificates(serving.Cert)
+	if err != nil {
+		return "the certificate can't be read"
+	}
+	certificate := certificates[0]
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca)
+	if _, err := certificate.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}); err != nil {
+		return "the certificate isn't signed by the current CA"
+	}
+	for _, name := range dnsNames {
+		if !slices.Contains(certificate.DNSNames, name) {
+			return fmt.Sprintf("the certificate doesn't cover %s", name)
+		}
+	}
+	if !now.Add(renewBefore).Before(certificate.NotAfter) {
+		return "the certificate expires soon"
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"testing"
+	"time"
+)
+
+var dnsNames = []string{"high-overcommit-webhook.k8s-overcommit.svc", "high-overcommit-webhook.k8s-overcommit.svc.cluster.local"}
+
+func TestNeedsRenewal(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	ca, err := newCA(now, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, err := newCA(now, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serving, err := newServing(ca, dnsNames, now, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		ca       []byte
+		dnsNames []string
+		now      time.Time
+		expected string
+	}{
+		{
+			name:     "valid",
+			ca:       ca.Cert,
+			dnsNames: dnsNames,
+			now:      now,
+		},
+		{
+			name:     "signed by a bundle with the CA",
+			ca:       bundle(now, otherCA.Cert, ca.Cert),
+			dnsNames: dnsNames,
+			now:      now,
+		},
+		{
+			name:     "signed by another CA",
+			ca:       otherCA.Cert,
+			dnsNames: dnsNames,
+			now:      now,
+			expected: "the certificate isn't signed by the current CA",
+		},
+		{
+			name:     "new DNS name",
+			ca:       ca.Cert,
+			dnsNames: append(dnsNames, "high-overcommit-webhook"),
+			now:      now,
+			expected: "the certificate doesn't cover high-overcommit-webhook",
+		},
+		{
+			name:     "within the renewal",
+			ca:       ca.Cert,
+			dnsNames: dnsNames,
+			now:      now.Add(50 * time.Minute),
+			expected: "the certificate expires soon",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if reason := needsRenewal(serving, test.ca, test.dnsNames, test.now, 15*time.Minute); reason != test.expected {
+				t.Errorf("Expected the reason %q, got %q", test.expected, reason)
+			}
+		})
+	}
+
+	if reason := needsRenewal(keyPair{Cert: serving.Cert, Key: ca.Key}, ca.Cert, dnsNames, now, 0); reason != "the certificate can't be read" {
+		t.Errorf("Expected a certificate with another key not to be read, got %q", reason)
+	}
+}
+
+func TestServingCappedByCA(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	ca, err := newCA(now, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serving, err := newServing(ca, dnsNames, now, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificates, err := parseCertificates(serving.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certificates[0].NotAfter.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected the certificate to expire with the CA, got %s", certificates[0].NotAfter)
+	}
+}
+
+func TestBundle(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	previous, err := newCA(now.Add(-2*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := newCA(now, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificates, err := parseCertificates(bundle(now.Add(-90*time.Minute), current.Cert, previous.Cert, current.Cert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certificates) != 2 {
+		t.Errorf("Expected the bundle to hold both CAs once, got %d certificates", len(certificates))
+	}
+
+	certificates, err = parseCertificates(bundle(now, current.Cert, previous.Cert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certificates) != 1 {
+		t.Errorf("Expected the expired CA to be dropped, got %d certificates", len(certificates))
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reloader serves the certificate of the webhook server from the tls.crt and tls.key files of a
+// directory and reloads it when they change, whatever the provider that rotated it. Besides
+// watching the files, it reads them every interval, as the kubelet updates the mounted secrets
+// by swapping symlinks, which a file watch can miss.
+type Reloader struct {
+	*certwatcher.CertWatcher
+	certPath string
+	keyPath  string
+	interval time.Duration
+	current  []byte
+}
+
+// NewReloader returns a reloader of the certificate of the directory, read at once.
+func NewReloader(dir string, interval time.Duration) (*Reloader, error) {
+	reloader := &Reloader{
+		certPath: filepath.Join(dir, "tls.crt"),
+		keyPath:  filepath.Join(dir, "tls.key"),
+		interval: interval,
+	}
+	watcher, err := certwatcher.New(reloader.certPath, reloader.keyPath)
+	if err != nil {
+		return nil, err
+	}
+	reloader.CertWatcher = watcher
+	reloader.current, _ = reloader.read()
+	return reloader, nil
+}
+
+// ConfigureTLS makes the webhook server serve the certificate of the reloader.
+func (r *Reloader) ConfigureTLS(config *tls.Config) {
+	config.GetCertificate = r.GetCertificate
+}
+
+// Start watches and reads the files until the context is done.
+func (r *Reloader) Start(ctx context.Context) error {
+	go wait.UntilWithContext(ctx, r.poll, r.interval)
+	return r.CertWatcher.Start(ctx)
+}
+
+// NeedLeaderElection returns false, every replica serves the webhooks.
+func (r *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// poll reloads the certificate when the files changed since the last read.
+func (r *Reloader) poll(ctx context.Context) {
+	content, err := r.read()
+	if err != nil || bytes.Equal(content, r.current) {
+		return
+	}
+	if err := r.ReadCertificate(); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to reload the serving certificate", "path", r.certPath)
+		return
+	}
+	r.current = content
+}
+
+func (r *Reloader) read() ([]byte, error) {
+	cert, err := os.ReadFile(r.certPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := os.ReadFile(r.keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return append(cert, key...), nil
+}
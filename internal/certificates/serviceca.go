@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations of the service CA of OpenShift.
+const (
+	// ServingCertSecretAnnotation makes the service CA issue the certificate of a Service into
+	// the secret.
+	ServingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	// InjectCABundleAnnotation makes the service CA inject its CA bundle in the webhook
+	// configurations and the CRDs.
+	InjectCABundleAnnotation = "service.beta.openshift.io/inject-cabundle"
+	// originatingServiceAnnotation is set by the service CA on the secrets it issues.
+	originatingServiceAnnotation = "service.beta.openshift.io/originating-service-name"
+)
+
+// ServiceCA issues the certificates with the service CA of OpenShift, from the annotations of
+// the Services of the webhooks. The service CA rotates them and injects the CA bundle.
+type ServiceCA struct {
+	Options
+}
+
+// Prepare deletes the Issuer of cert-manager.
+func (p *ServiceCA) Prepare(ctx context.Context) error {
+	return deleteCertManagerObject(ctx, p.Client, resources.GenerateIssuer(p.Namespace))
+}
+
+// Issue deletes the Certificate, and the secret when another provider wrote it, as the service
+// CA only issues certificates into secrets it created.
+func (p *ServiceCA) Issue(ctx context.Context, _ client.Object, certificate *certmanagerv1.Certificate) error {
+	if err := deleteCertManagerObject(ctx, p.Client, certificate.DeepCopy()); err != nil {
+		return err
+	}
+	secret := &corev1.Secret{}
+	if err := p.Reader.Get(ctx, p.secretKey(certificate.Spec.SecretName), secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, ok := secret.Annotations[originatingServiceAnnotation]; ok {
+		return nil
+	}
+	if err := client.IgnoreNotFound(p.Client.Delete(ctx, secret)); err != nil {
+		return fmt.Errorf("error deleting the secret %s for the service CA: %w", secret.Name, err)
+	}
+	return nil
+}
+
+// CABundle returns nil, the service CA injects the CA bundle.
+func (p *ServiceCA) CABundle(context.Context) ([]byte, error) {
+	return nil, nil
+}
+
+// Annotate makes the service CA issue the certificate for the Service, and inject its CA
+// bundle in the webhook configurations and the CRDs instead of cert-manager.
+func (p *ServiceCA) Annotate(object client.Object, certificate *certmanagerv1.Certificate) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	switch object.(type) {
+	case *corev1.Service:
+		annotations[ServingCertSecretAnnotation] = certificate.Spec.SecretName
+	case *admissionv1.MutatingWebhookConfiguration, *admissionv1.ValidatingWebhookConfiguration, *apiextensionsv1.CustomResourceDefinition:
+		delete(annotations, InjectCAAnnotation)
+		annotations[InjectCABundleAnnotation] = "true"
+	}
+	object.SetAnnotations(annotations)
+}
+
+// Authority returns nothing, the service CA is part of OpenShift.
+func (p *ServiceCA) Authority(context.Context) []Status {
+	return nil
+}
+
+// Check returns the readiness of the secret of the certificate.
+func (p *ServiceCA) Check(ctx context.Context, certificate *certmanagerv1.Certificate) Status {
+	return Status{
+		Kind:   "Secret",
+		Name:   certificate.Spec.SecretName,
+		Result: readiness.Check(ctx, p.Reader, "Secret", p.secretKey(certificate.Spec.SecretName), &corev1.Secret{}, readiness.Secret),
+	}
+}
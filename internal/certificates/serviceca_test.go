@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestServiceCAAnnotate(t *testing.T) {
+	provider := &ServiceCA{}
+	certificate := servingCertificate(dnsNames...)
+
+	service := &corev1.Service{}
+	provider.Annotate(service, certificate)
+	if service.Annotations[ServingCertSecretAnnotation] != "high-overcommit-webhook-secret" {
+		t.Errorf("Expected the service CA to issue the certificate of the service, got %v", service.Annotations)
+	}
+
+	configuration := &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{InjectCAAnnotation: "k8s-overcommit/high-overcommit-webhook-certificate"}},
+	}
+	provider.Annotate(configuration, certificate)
+	if _, ok := configuration.Annotations[InjectCAAnnotation]; ok || configuration.Annotations[InjectCABundleAnnotation] != "true" {
+		t.Errorf("Expected the service CA to inject the CA bundle instead of cert-manager, got %v", configuration.Annotations)
+	}
+}
+
+func TestServiceCAIssue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	builtin, c, _ := newBuiltin(t, &now)
+	provider := &ServiceCA{Options: builtin.Options}
+	certificate := servingCertificate(dnsNames...)
+	key := client.ObjectKey{Name: certificate.Spec.SecretName, Namespace: "k8s-overcommit"}
+
+	// The secrets issued by the service CA are kept
+	issued := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        key.Name,
+		Namespace:   key.Namespace,
+		Annotations: map[string]string{originatingServiceAnnotation: "high-overcommit-webhook"},
+	}}
+	if err := c.Create(ctx, issued); err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Issue(ctx, nil, certificate); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, key, &corev1.Secret{}); err != nil {
+		t.Errorf("Expected the secret of the service CA to be kept, got %v", err)
+	}
+
+	// The secrets of another provider are deleted so that the service CA issues them
+	if err := c.Delete(ctx, issued); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Issue(ctx, nil, certificate); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, key, &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the secret of another provider to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package conditions computes the conditions of the Overcommit and OvercommitClass resources
+// from the readiness of the resources generated for them.
+package conditions
+
+import (
+	"fmt"
+	"strings"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the conditions.
+const (
+	ReasonAllResourcesReady   = "AllResourcesReady"
+	ReasonResourcesNotReady   = "ResourcesNotReady"
+	ReasonDeploymentsReady    = "DeploymentsAvailable"
+	ReasonDeploymentsNotReady = "DeploymentsUnavailable"
+	ReasonReconciling         = "Reconciling"
+	ReasonReconciled          = "Reconciled"
+	ReasonFailed              = "ResourcesFailed"
+	ReasonAsExpected          = "AsExpected"
+	ReasonWebhookReachable    = "WebhookReachable"
+	ReasonWebhookUnreachable  = "WebhookUnreachable"
+)
+
+// progressingReasons are the readiness reasons of a resource that is still being created,
+// rolled out or issued.
+var progressingReasons = map[string]bool{
+	readiness.ReasonNotFound:          true,
+	readiness.ReasonRolloutInProgress: true,
+	readiness.ReasonNotIssued:         true,
+	readiness.ReasonCABundleMissing:   true,
+}
+
+// Set sets the ResourcesReady, Available, Progressing, Degraded and WebhookReachable conditions
+// from the status of the resources, for the given generation.
+func Set(conditions *[]metav1.Condition, resources []overcommit.ResourceStatus, generation int64) {
+	var notReady, deployments, progressing, failed, webhooks []overcommit.ResourceStatus
+	for _, resource := range resources {
+		if resource.Ready {
+			continue
+		}
+		notReady = append(notReady, resource)
+		if resource.Kind == "Deployment" {
+			deployments = append(deployments, resource)
+		}
+		if strings.HasSuffix(resource.Kind, "WebhookConfiguration") {
+			webhooks = append(webhooks, resource)
+		}
+		if progressingReasons[resource.Reason] {
+			progressing = append(progressing, resource)
+		} else {
+			failed = append(failed, resource)
+		}
+	}
+
+	set(conditions, generation, overcommit.ConditionResourcesReady, len(notReady) == 0,
+		ReasonAllResourcesReady, "All managed resources are ready",
+		ReasonResourcesNotReady, "Some resources are not ready: "+Message(notReady))
+	set(conditions, generation, overcommit.ConditionAvailable, len(deployments) == 0,
+		ReasonDeploymentsReady, "Every deployment is available",
+		ReasonDeploymentsNotReady, Message(deployments))
+	set(conditions, generation, overcommit.ConditionProgressing, len(progressing) > 0,
+		ReasonReconciling, Message(progressing),
+		ReasonReconciled, "Every resource is created and rolled out")
+	set(conditions, generation, overcommit.ConditionDegraded, len(failed) > 0,
+		ReasonFailed, Message(failed),
+		ReasonAsExpected, "No resource failed")
+	set(conditions, generation, overcommit.ConditionWebhookReachable, len(webhooks) == 0,
+		ReasonWebhookReachable, "Every webhook has its CA bundle and ready endpoints",
+		ReasonWebhookUnreachable, Message(webhooks))
+}
+
+// Message lists the resources with the message of their readiness.
+func Message(resources []overcommit.ResourceStatus) string {
+	messages := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		messages = append(messages, fmt.Sprintf("%s %s: %s", resource.Kind, resource.Name, resource.Message))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func set(conditions *[]metav1.Condition, generation int64, conditionType string, status bool, trueReason, trueMessage, falseReason, falseMessage string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             trueReason,
+		Message:            trueMessage,
+		ObservedGeneration: generation,
+	}
+	if !status {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = falseReason
+		condition.Message = falseMessage
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
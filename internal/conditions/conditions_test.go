@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package conditions
+
+import (
+	"testing"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []overcommit.ResourceStatus
+		expected  map[string]metav1.ConditionStatus
+	}{
+		{
+			name: "every resource ready",
+			resources: []overcommit.ResourceStatus{
+				{Kind: "Deployment", Name: "webhook", Ready: true, Reason: readiness.ReasonAvailable},
+				{Kind: "MutatingWebhookConfiguration", Name: "webhook", Ready: true, Reason: readiness.ReasonWebhookReady},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				overcommit.ConditionResourcesReady:   metav1.ConditionTrue,
+				overcommit.ConditionAvailable:        metav1.ConditionTrue,
+				overcommit.ConditionProgressing:      metav1.ConditionFalse,
+				overcommit.ConditionDegraded:         metav1.ConditionFalse,
+				overcommit.ConditionWebhookReachable: metav1.ConditionTrue,
+			},
+		},
+		{
+			name: "rolling out",
+			resources: []overcommit.ResourceStatus{
+				{Kind: "Deployment", Name: "webhook", Reason: readiness.ReasonRolloutInProgress},
+				{Kind: "MutatingWebhookConfiguration", Name: "webhook", Ready: true, Reason: readiness.ReasonWebhookReady},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				overcommit.ConditionResourcesReady:   metav1.ConditionFalse,
+				overcommit.ConditionAvailable:        metav1.ConditionFalse,
+				overcommit.ConditionProgressing:      metav1.ConditionTrue,
+				overcommit.ConditionDegraded:         metav1.ConditionFalse,
+				overcommit.ConditionWebhookReachable: metav1.ConditionTrue,
+			},
+		},
+		{
+			name: "crashlooping webhook",
+			resources: []overcommit.ResourceStatus{
+				{Kind: "Deployment", Name: "webhook", Reason: readiness.ReasonUnavailable},
+				{Kind: "MutatingWebhookConfiguration", Name: "webhook", Reason: readiness.ReasonNoReadyEndpoints},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				overcommit.ConditionResourcesReady:   metav1.ConditionFalse,
+				overcommit.ConditionAvailable:        metav1.ConditionFalse,
+				overcommit.ConditionProgressing:      metav1.ConditionFalse,
+				overcommit.ConditionDegraded:         metav1.ConditionTrue,
+				overcommit.ConditionWebhookReachable: metav1.ConditionFalse,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditions := []metav1.Condition{}
+			Set(&conditions, test.resources, 4)
+			for conditionType, status := range test.expected {
+				condition := meta.FindStatusCondition(conditions, conditionType)
+				if condition == nil {
+					t.Fatalf("Expected condition %s to be set", conditionType)
+				}
+				if condition.Status != status {
+					t.Errorf("Expected condition %s to be %s, got %s: %s", conditionType, status, condition.Status, condition.Message)
+				}
+				if condition.ObservedGeneration != 4 {
+					t.Errorf("Expected condition %s to observe generation 4, got %d", conditionType, condition.ObservedGeneration)
+				}
+			}
+		})
+	}
+}
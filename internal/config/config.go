@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package config loads the configuration of a process of the operator from a versioned file and
+// flags, and validates it at startup. The configuration is passed explicitly to the reconcilers,
+// the generators of the resources and the webhooks.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Version of the configuration file.
+const (
+	APIVersion = "config.overcommit.inditex.dev/v1"
+	Kind       = "OperatorConfig"
+)
+
+// Defaults of the configuration.
+const (
+	DefaultCertDir      = "/etc/webhook/config"
+	DefaultResyncPeriod = 10 * time.Minute
+)
+
+// Role is what a process of the operator runs.
+type Role string
+
+const (
+	// RoleOperator runs the Overcommit controller, which deploys the other roles.
+	RoleOperator Role = "operator"
+	// RoleClassController runs the OvercommitClass controller.
+	RoleClassController Role = "class-controller"
+	// RoleMutatingWebhook serves the pod mutating webhook.
+	RoleMutatingWebhook Role = "mutating-webhook"
+	// RoleValidatingWebhook serves the pod validating webhook.
+	RoleValidatingWebhook Role = "validating-webhook"
+	// RoleClassWebhook serves the OvercommitClass validating webhook and the conversion of the
+	// OvercommitClasses.
+	RoleClassWebhook Role = "class-webhook"
+)
+
+// Roles are the valid roles.
+var Roles = []Role{RoleOperator, RoleClassController, RoleMutatingWebhook, RoleValidatingWebhook, RoleClassWebhook}
+
+// Webhook tells whether the role serves webhooks.
+func (r Role) Webhook() bool {
+	return r == RoleMutatingWebhook || r == RoleValidatingWebhook || r == RoleClassWebhook
+}
+
+// Config is the configuration of a process of the operator.
+type Config struct {
+	metav1.TypeMeta `json:",inline"`
+	// Role of the process.
+	Role Role `json:"role"`
+	// Namespace the operator runs in, where the resources are generated.
+	Namespace string `json:"namespace"`
+	// PodName is the name of the pod of the process.
+	PodName string `json:"podName"`
+	// Image of the generated deployments. The operator uses its own image when it isn't set.
+	Image string `json:"image,omitempty"`
+	// ServiceAccountName of the generated deployments. The operator uses its own service account
+	// when it isn't set.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// ClassName is the class of a mutating webhook deployed for a single class.
+	ClassName string `json:"className,omitempty"`
+	// CertDir holds the serving certificate of the webhooks.
+	CertDir string `json:"certDir,omitempty"`
+	// ResyncPeriod requeues every resource periodically. Zero disables it.
+	ResyncPeriod metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+// Version returns the tag of the image, the version of the operator.
+func (c Config) Version() string {
+	name := c.Image[strings.LastIndex(c.Image, "/")+1:]
+	if index := strings.LastIndex(name, ":"); index >= 0 {
+		return name[index+1:]
+	}
+	return "latest"
+}
+
+// Default fills the fields that aren't set. The namespace and the name of the pod default to the
+// POD_NAMESPACE and POD_NAME variables set from the downward API.
+func (c *Config) Default() {
+	if c.APIVersion == "" {
+		c.APIVersion = APIVersion
+	}
+	if c.Kind == "" {
+		c.Kind = Kind
+	}
+	if c.Namespace == "" {
+		c.Namespace = os.Getenv("POD_NAMESPACE")
+	}
+	if c.PodName == "" {
+		c.PodName = os.Getenv("POD_NAME")
+	}
+	if c.CertDir == "" {
+		c.CertDir = DefaultCertDir
+	}
+}
+
+// Validate returns every error of the configuration.
+func (c Config) Validate() error {
+	var errs []error
+	if c.APIVersion != APIVersion || c.Kind != Kind {
+		errs = append(errs, fmt.Errorf("unsupported configuration %s %s, expected %s %s", c.APIVersion, c.Kind, APIVersion, Kind))
+	}
+	valid := false
+	for _, role := range Roles {
+		valid = valid || c.Role == role
+	}
+	if !valid {
+		errs = append(errs, fmt.Errorf("invalid role %q, expected one of %v", c.Role, Roles))
+	}
+	if c.Namespace == "" {
+		errs = append(errs, errors.New("the namespace is required"))
+	}
+	if c.PodName == "" {
+		errs = append(errs, errors.New("the pod name is required"))
+	}
+	if c.Role == RoleClassController && (c.Image == "" || c.ServiceAccountName == "") {
+		errs = append(errs, errors.New("the class controller requires the image and the service account of the webhooks"))
+	}
+	if c.ClassName != "" && c.Role != RoleMutatingWebhook {
+		errs = append(errs, fmt.Errorf("the class name is only valid for the %s role", RoleMutatingWebhook))
+	}
+	if c.ResyncPeriod.Duration < 0 {
+		errs = append(errs, errors.New("the resync period can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Flags are the flags of the configuration. The flags that are set override the configuration
+// file.
+type Flags struct {
+	file      string
+	values    Config
+	overrides map[string]func(*Config)
+	flagSet   *flag.FlagSet
+}
+
+// BindFlags registers the flags of the configuration in the flag set.
+func BindFlags(flagSet *flag.FlagSet) *Flags {
+	f := &Flags{flagSet: flagSet, overrides: map[string]func(*Config){}}
+	flagSet.StringVar(&f.file, "config", "", "The configuration file, of kind "+Kind+". The flags that are set override it.")
+	f.string("role", (*string)(&f.values.Role), func(c *Config) *string { return (*string)(&c.Role) },
+		fmt.Sprintf("What the process runs, one of %v.", Roles))
+	f.string("namespace", &f.values.Namespace, func(c *Config) *string { return &c.Namespace },
+		"The namespace the operator runs in. Defaults to the POD_NAMESPACE variable.")
+	f.string("pod-name", &f.values.PodName, func(c *Config) *string { return &c.PodName },
+		"The name of the pod of the process. Defaults to the POD_NAME variable.")
+	f.string("image", &f.values.Image, func(c *Config) *string { return &c.Image },
+		"The image of the generated deployments. Defaults to the image of the operator.")
+	f.string("service-account-name", &f.values.ServiceAccountName, func(c *Config) *string { return &c.ServiceAccountName },
+		"The service account of the generated deployments. Defaults to the service account of the operator.")
+	f.string("class-name", &f.values.ClassName, func(c *Config) *string { return &c.ClassName },
+		"The class of a mutating webhook deployed for a single class.")
+	f.string("cert-dir", &f.values.CertDir, func(c *Config) *string { return &c.CertDir },
+		"The directory of the serving certificate of the webhooks.")
+	flagSet.DurationVar(&f.values.ResyncPeriod.Duration, "resync-period", DefaultResyncPeriod,
+		"How often the controllers reconcile every resource even without changes. Use 0 to disable it.")
+	f.overrides["resync-period"] = func(c *Config) { c.ResyncPeriod = f.values.ResyncPeriod }
+	return f
+}
+
+func (f *Flags) string(name string, value *string, field func(*Config) *string, usage string) {
+	f.flagSet.StringVar(value, name, "", usage)
+	f.overrides[name] = func(c *Config) { *field(c) = *value }
+}
+
+// Load reads the configuration file, overrides it with the flags that are set, fills the
+// defaults and validates the configuration.
+func (f *Flags) Load() (Config, error) {
+	config := Config{ResyncPeriod: metav1.Duration{Duration: DefaultResyncPeriod}}
+	if f.file != "" {
+		content, err := os.ReadFile(f.file)
+		if err != nil {
+			return Config{}, fmt.Errorf("error reading the configuration file: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(content, &config); err != nil {
+			return Config{}, fmt.Errorf("error parsing the configuration file %s: %w", f.file, err)
+		}
+	}
+	f.flagSet.Visit(func(set *flag.Flag) {
+		if override, ok := f.overrides[set.Name]; ok {
+			override(&config)
+		}
+	})
+	if config.Role == "" {
+		config.Role = legacyRole()
+	}
+	config.Default()
+	if err := config.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return config, nil
+}
+
+// legacyRole returns the role set by the variables of the previous releases, so that the
+// deployments that still set them keep working.
+//
+// Deprecated: use the role flag or the configuration file.
+func legacyRole() Role {
+	switch {
+	case os.Getenv("ENABLE_OVERCOMMIT_CONTROLLER") == "true":
+		return RoleOperator
+	case os.Getenv("ENABLE_OVERCOMMIT_CLASS_CONTROLLER") == "true":
+		return RoleClassController
+	case os.Getenv("ENABLE_POD_MUTATING_WEBHOOK") == "true":
+		return RoleMutatingWebhook
+	case os.Getenv("ENABLE_POD_VALIDATING_WEBHOOK") == "true":
+		return RoleValidatingWebhook
+	case os.Getenv("ENABLE_OC_VALIDATING_WEBHOOK") == "true":
+		return RoleClassWebhook
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func load(t *testing.T, file string, args ...string) (Config, error) {
+	t.Helper()
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := BindFlags(flagSet)
+	if file != "" {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		args = append([]string{"--config=" + path}, args...)
+	}
+	if err := flagSet.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return flags.Load()
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "k8s-overcommit")
+	t.Setenv("POD_NAME", "k8s-overcommit-0")
+
+	cfg, err := load(t, `apiVersion: config.overcommit.inditex.dev/v1
+kind: OperatorConfig
+role: class-controller
+image: registry/k8s-overcommit:v1.2.0
+serviceAccountName: k8s-overcommit
+resyncPeriod: 5m
+`, "--resync-period=1m", "--namespace=operators")
+	if err != nil {
+		t.Fatalf("Expected a valid configuration, got %v", err)
+	}
+	if cfg.Role != RoleClassController || cfg.Image != "registry/k8s-overcommit:v1.2.0" {
+		t.Errorf("Expected the role and the image of the file, got %s %s", cfg.Role, cfg.Image)
+	}
+	if cfg.Namespace != "operators" || cfg.ResyncPeriod.Duration != time.Minute {
+		t.Errorf("Expected the flags to override the file, got %s %s", cfg.Namespace, cfg.ResyncPeriod.Duration)
+	}
+	if cfg.PodName != "k8s-overcommit-0" || cfg.CertDir != DefaultCertDir {
+		t.Errorf("Expected the defaults, got %s %s", cfg.PodName, cfg.CertDir)
+	}
+}
+
+func TestLoadFlags(t *testing.T) {
+	cfg, err := load(t, "", "--role=mutating-webhook", "--namespace=k8s-overcommit", "--pod-name=webhook-0", "--class-name=high")
+	if err != nil {
+		t.Fatalf("Expected a valid configuration, got %v", err)
+	}
+	if cfg.ClassName != "high" || cfg.ResyncPeriod.Duration != DefaultResyncPeriod {
+		t.Errorf("Expected the class of the flag and the default resync period, got %s %s", cfg.ClassName, cfg.ResyncPeriod.Duration)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "unknown field",
+			file:     "apiVersion: config.overcommit.inditex.dev/v1\nkind: OperatorConfig\nrole: operator\nunknown: true\n",
+			expected: []string{"unknown"},
+		},
+		{
+			name:     "unsupported version",
+			file:     "apiVersion: config.overcommit.inditex.dev/v2\nkind: OperatorConfig\nrole: operator\n",
+			args:     []string{"--namespace=k8s-overcommit", "--pod-name=operator-0"},
+			expected: []string{"unsupported configuration"},
+		},
+		{
+			name:     "every error",
+			args:     []string{"--role=class-controller", "--class-name=high", "--resync-period=-1m"},
+			expected: []string{"namespace", "pod name", "image", "class name", "resync period"},
+		},
+		{
+			name:     "invalid role",
+			args:     []string{"--role=controller", "--namespace=k8s-overcommit", "--pod-name=operator-0"},
+			expected: []string{`invalid role "controller"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("POD_NAMESPACE", "")
+			t.Setenv("POD_NAME", "")
+			_, err := load(t, test.file, test.args...)
+			if err == nil {
+				t.Fatal("Expected an invalid configuration")
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected the error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadLegacyRole(t *testing.T) {
+	tests := map[string]Role{
+		"ENABLE_POD_VALIDATING_WEBHOOK": RoleValidatingWebhook,
+		"ENABLE_OC_VALIDATING_WEBHOOK":  RoleClassWebhook,
+	}
+	for variable, expected := range tests {
+		t.Run(variable, func(t *testing.T) {
+			t.Setenv(variable, "true")
+
+			cfg, err := load(t, "", "--namespace=k8s-overcommit", "--pod-name=webhook-0")
+			if err != nil {
+				t.Fatalf("Expected a valid configuration, got %v", err)
+			}
+			if cfg.Role != expected {
+				t.Errorf("Expected the role of the variable, got %s", cfg.Role)
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	tests := map[string]string{
+		"registry:5000/k8s-overcommit:v1.2.0": "v1.2.0",
+		"registry:5000/k8s-overcommit":        "latest",
+		"k8s-overcommit:1.0":                  "1.0",
+	}
+	for image, expected := range tests {
+		if version := (Config{Image: image}).Version(); version != expected {
+			t.Errorf("Expected version %s of %s, got %s", expected, image, version)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/events"
+)
+
+// maxNamespacesToRelabel is the maximum number of namespaces listed in the status in the DryRun
+// mode of namespaceRelabel.
+const maxNamespacesToRelabel = 50
+
+// listPageSize is the number of objects read per request when counting the pending objects of a
+// migration, so a large cluster is never listed in a single request.
+const listPageSize = 500
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// updateLabelMigration records in the status a change of overcommitLabel and the progress of its
+// migration, relabeling the pending namespaces in the Enabled mode of namespaceRelabel. The
+// migration is completed at the end of its grace period.
+func (r *OvercommitReconciler) updateLabelMigration(ctx context.Context, overcommitObject *overcommit.Overcommit, now time.Time) error {
+	status := &overcommitObject.Status
+	label := overcommitObject.Spec.OvercommitLabel
+	if status.OvercommitLabel != "" && status.OvercommitLabel != label {
+		status.LabelMigration = &overcommit.LabelMigrationStatus{
+			From:      status.OvercommitLabel,
+			To:        label,
+			Phase:     overcommit.LabelMigrationInProgress,
+			StartTime: metav1.NewTime(now),
+			Deadline:  metav1.NewTime(now.Add(overcommitObject.LabelMigrationGracePeriod())),
+		}
+	}
+	status.OvercommitLabel = label
+
+	migration := status.LabelMigration
+	if migration == nil || migration.Phase != overcommit.LabelMigrationInProgress {
+		return nil
+	}
+	if !now.Before(migration.Deadline.Time) {
+		migration.Phase = overcommit.LabelMigrationCompleted
+		migration.NamespacesToRelabel = nil
+		return nil
+	}
+
+	selector, err := pendingSelector(migration)
+	if err != nil {
+		return err
+	}
+	pods, err := r.listMetadata(ctx, "PodList", selector)
+	if err != nil {
+		return err
+	}
+	namespaces, err := r.listMetadata(ctx, "NamespaceList", selector)
+	if err != nil {
+		return err
+	}
+	migration.PendingPods = int32(len(pods))
+	migration.PendingNamespaces = int32(len(namespaces))
+	migration.NamespacesToRelabel = nil
+
+	mode := overcommit.NamespaceRelabelDisabled
+	if overcommitObject.Spec.LabelMigration != nil && overcommitObject.Spec.LabelMigration.NamespaceRelabel != "" {
+		mode = overcommitObject.Spec.LabelMigration.NamespaceRelabel
+	}
+	switch mode {
+	case overcommit.NamespaceRelabelDryRun:
+		for _, namespace := range namespaces {
+			migration.NamespacesToRelabel = append(migration.NamespacesToRelabel, namespace.Name)
+		}
+		slices.Sort(migration.NamespacesToRelabel)
+		if len(migration.NamespacesToRelabel) > maxNamespacesToRelabel {
+			migration.NamespacesToRelabel = migration.NamespacesToRelabel[:maxNamespacesToRelabel]
+		}
+	case overcommit.NamespaceRelabelEnabled:
+		relabeled := 0
+		for i := range namespaces {
+			if err := r.relabelNamespace(ctx, &namespaces[i], migration.From, migration.To); err != nil {
+				logf.FromContext(ctx).Error(err, "Failed to relabel namespace", "namespace", namespaces[i].Name)
+				continue
+			}
+			relabeled++
+		}
+		if relabeled > 0 {
+			events.NamespacesRelabeled(r.Recorder, overcommitObject, relabeled, migration.From, migration.To)
+		}
+		migration.RelabeledNamespaces += int32(relabeled)
+		migration.PendingNamespaces -= int32(relabeled)
+	}
+	return nil
+}
+
+// pendingSelector selects the objects labeled with the previous label and not with the new one.
+func pendingSelector(migration *overcommit.LabelMigrationStatus) (labels.Selector, error) {
+	from, err := labels.NewRequirement(migration.From, selection.Exists, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting the previous label %s: %w", migration.From, err)
+	}
+	to, err := labels.NewRequirement(migration.To, selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting the new label %s: %w", migration.To, err)
+	}
+	return labels.NewSelector().Add(*from, *to), nil
+}
+
+// listMetadata lists the metadata of the core objects of the list kind matching the selector,
+// from the API server as pods are not cached, listPageSize objects at a time.
+func (r *OvercommitReconciler) listMetadata(ctx context.Context, listKind string, selector labels.Selector) ([]metav1.PartialObjectMetadata, error) {
+	var items []metav1.PartialObjectMetadata
+	options := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}, client.Limit(listPageSize)}
+	for {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(listKind))
+		if err := r.reader().List(ctx, list, options...); err != nil {
+			return nil, fmt.Errorf("error listing %s: %w", listKind, err)
+		}
+		items = append(items, list.Items...)
+		if list.Continue == "" {
+			return items, nil
+		}
+		options = append(options[:2], client.Continue(list.Continue))
+	}
+}
+
+// relabelNamespace moves the value of the previous label of the namespace to the new label.
+func (r *OvercommitReconciler) relabelNamespace(ctx context.Context, namespace *metav1.PartialObjectMetadata, from, to string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{to: namespace.Labels[from], from: nil},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	namespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	return r.Patch(ctx, namespace, client.RawPatch(types.MergePatchType, patch))
+}
@@ -8,17 +8,30 @@ package controller
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/events"
 	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 )
@@ -29,6 +42,17 @@ var logger = logf.Log.WithName("overcommit_controller")
 type OvercommitReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder records the lifecycle events of the generated resources on the Overcommit resource
+	Recorder record.EventRecorder
+	// ResyncPeriod requeues the Overcommit resource periodically as a safety net for missed
+	// events. Zero disables it.
+	ResyncPeriod time.Duration
+	// APIReader lists the pods and namespaces pending a label migration, the endpoints of the
+	// webhooks and reads the secrets of the certificates without caching them. The client is
+	// used when it is not set.
+	APIReader client.Reader
+	// Config is the configuration of the operator the resources are generated with.
+	Config config.Config
 }
 
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommits,verbs=get;list;watch;create;update;patch;delete
@@ -47,15 +71,9 @@ type OvercommitReconciler struct {
 func (r *OvercommitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger.Info("Starting reconciliation", "name", req.Name, "namespace", req.Namespace, "time", time.Now().Format("15:04:05"))
 
-	label, err := utils.GetOvercommitLabel(ctx, r.Client)
-	if err != nil {
-		logger.Error(err, "Failed to get Overcommit")
-		return ctrl.Result{}, err
-	}
-
 	overcommit := &overcommit.Overcommit{}
 
-	err = r.Get(ctx, req.NamespacedName, overcommit)
+	err := r.Get(ctx, req.NamespacedName, overcommit)
 	if err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			return ctrl.Result{}, err
@@ -98,289 +116,139 @@ func (r *OvercommitReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 		// Return early to trigger a new reconciliation with the updated object
 		logger.Info("Finalizer added, requeuing reconciliation")
-		return ctrl.Result{}, nil
+		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Both the previous and the new overcommitLabel are honored during a label migration
+	labels := overcommit.OvercommitLabels(time.Now())
+
 	// Reconcile Issuer
-	issuer := resources.GenerateIssuer()
+	issuer := resources.GenerateIssuer(r.Config.Namespace)
 	if issuer == nil {
 		logger.Error(nil, "Generated issuer is nil")
 		return ctrl.Result{}, fmt.Errorf("Generated issuer is nil")
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, issuer, func() error {
-		// Only set controller reference if this is a new resource
-		if issuer.CreationTimestamp.IsZero() {
-			return ctrl.SetControllerReference(overcommit, issuer, r.Scheme)
-		}
-		return nil
-	})
+	// The certificates are issued with the Issuer, the CA of the operator, by the user or by the
+	// service CA of OpenShift
+	provider := r.certificateProvider(overcommit)
+	if err := provider.Prepare(ctx); err != nil {
+		logger.Error(err, "Failed to prepare the certificate provider")
+		return ctrl.Result{}, err
+	}
+	caBundle, err := provider.CABundle(ctx)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile issuer")
+		logger.Error(err, "Failed to get the CA bundle")
 		return ctrl.Result{}, err
 	}
 
 	// Reconcile OvercommitClassValidator
-	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommit)
+	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommit, r.Config)
 	overcommitClassService := resources.GenerateOvercommitClassValidatingService(*overcommitClassDeployment)
 	overcommitClassCertificate := resources.GenerateCertificateValidatingOvercommitClass(*issuer, *overcommitClassService)
 	overcommitClassWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate)
+	overcommitClassBudget := resources.GeneratePodDisruptionBudget(*overcommitClassDeployment, overcommit.Spec.Deployment)
+	certificates.InjectCABundle(overcommitClassWebhook, caBundle)
+	provider.Annotate(overcommitClassService, overcommitClassCertificate)
+	provider.Annotate(overcommitClassWebhook, overcommitClassCertificate)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassCertificate, func() error {
-		// Only set spec if this is a new resource or there are changes
-		if overcommitClassCertificate.CreationTimestamp.IsZero() {
-			updatedCertificate := resources.GenerateCertificateValidatingOvercommitClass(*issuer, *overcommitClassService)
-			overcommitClassCertificate.Spec = updatedCertificate.Spec
-			return ctrl.SetControllerReference(overcommit, overcommitClassCertificate, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to reconcile OvercommitClass Certificate")
+	if err := provider.Issue(ctx, overcommit, overcommitClassCertificate); err != nil {
+		logger.Error(err, "Failed to issue the certificate of the OvercommitClass validating webhook")
 		return ctrl.Result{}, err
 	}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassDeployment, func() error {
-		// Regenerate the desired deployment spec
-		updatedDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommit)
-		updatedDeployment.Spec.Template.Spec.Containers[0].Image = os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION")
-
-		// Only update if there are actual differences
-		if overcommitClassDeployment.CreationTimestamp.IsZero() {
-			// New deployment, set everything
-			overcommitClassDeployment.Spec = updatedDeployment.Spec
-			overcommitClassDeployment.ObjectMeta.Labels = updatedDeployment.ObjectMeta.Labels
-			overcommitClassDeployment.ObjectMeta.Annotations = updatedDeployment.ObjectMeta.Annotations
-			return ctrl.SetControllerReference(overcommit, overcommitClassDeployment, r.Scheme)
-		} else {
-			// Existing deployment, only update specific fields if needed
-			updated := false
-			if updatedDeployment.Spec.Template.Spec.Containers[0].Image != overcommitClassDeployment.Spec.Template.Spec.Containers[0].Image {
-				overcommitClassDeployment.Spec.Template.Spec.Containers[0].Image = updatedDeployment.Spec.Template.Spec.Containers[0].Image
-				updated = true
-			}
-			// Update environment variables if they changed
-			if !envVarsEqual(updatedDeployment.Spec.Template.Spec.Containers[0].Env, overcommitClassDeployment.Spec.Template.Spec.Containers[0].Env) {
-				overcommitClassDeployment.Spec.Template.Spec.Containers[0].Env = updatedDeployment.Spec.Template.Spec.Containers[0].Env
-				updated = true
-			}
-			// Update template annotations if they changed
-			if !mapsEqual(updatedDeployment.Spec.Template.Annotations, overcommitClassDeployment.Spec.Template.Annotations) {
-				overcommitClassDeployment.Spec.Template.Annotations = updatedDeployment.Spec.Template.Annotations
-				updated = true
-			}
-			// Update template labels if they changed
-			if !mapsEqual(updatedDeployment.Spec.Template.Labels, overcommitClassDeployment.Spec.Template.Labels) {
-				overcommitClassDeployment.Spec.Template.Labels = updatedDeployment.Spec.Template.Labels
-				updated = true
-			}
-			// Only set controller reference if we actually updated something
-			if updated {
-				return ctrl.SetControllerReference(overcommit, overcommitClassDeployment, r.Scheme)
-			}
+	for _, object := range []client.Object{overcommitClassDeployment, overcommitClassBudget, overcommitClassService, overcommitClassWebhook} {
+		if err := r.apply(ctx, overcommit, object); err != nil {
+			logger.Error(err, "Failed to reconcile the OvercommitClass validating webhook")
+			return ctrl.Result{}, err
 		}
-
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to reconcile OvercommitClass Deployment")
-		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassService, func() error {
-		// Only update spec if this is a new resource
-		if overcommitClassService.CreationTimestamp.IsZero() {
-			updatedService := resources.GenerateOvercommitClassValidatingService(*overcommitClassDeployment)
-			overcommitClassService.Spec = updatedService.Spec
-			return ctrl.SetControllerReference(overcommit, overcommitClassService, r.Scheme)
-		}
-		return nil
-	})
+	err = r.reconcileConversion(ctx, overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate, provider, caBundle)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile OvercommitClass Service")
+		logger.Error(err, "Failed to reconcile OvercommitClass conversion")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassWebhook, func() error {
-		// Only update webhooks if this is a new resource
-		if overcommitClassWebhook.CreationTimestamp.IsZero() {
-			updatedWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate)
-			overcommitClassWebhook.Annotations = updatedWebhook.Annotations
-			overcommitClassWebhook.Webhooks = updatedWebhook.Webhooks
-			return ctrl.SetControllerReference(overcommit, overcommitClassWebhook, r.Scheme)
-		}
-		return nil
-	})
+	// Reconcile the pod mutating webhook shared by every class
+	err = r.reconcilePodMutatingWebhook(ctx, overcommit, *issuer, provider)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile OvercommitClass Webhook")
+		logger.Error(err, "Failed to reconcile the shared pod mutating webhook")
 		return ctrl.Result{}, err
 	}
 
 	// Reconcile PodValidator
-	validatingPodDeployment := resources.GeneratePodValidatingDeployment(*overcommit)
+	validatingPodDeployment := resources.GeneratePodValidatingDeployment(*overcommit, r.Config)
 	validatingPodService := resources.GeneratePodValidatingService(*validatingPodDeployment)
 	validatingpodCertificate := resources.GenerateCertificateValidatingPods(*issuer, *validatingPodService)
-	validatingPodWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, label)
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingpodCertificate, func() error {
-		// Only update spec if this is a new resource
-		if validatingpodCertificate.CreationTimestamp.IsZero() {
-			updatedCertificate := resources.GenerateCertificateValidatingPods(*issuer, *validatingPodService)
-			validatingpodCertificate.Spec = updatedCertificate.Spec
-			return ctrl.SetControllerReference(overcommit, validatingpodCertificate, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to reconcile Pod Validating Certificate")
+	validatingPodWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, labels)
+	validatingPodBudget := resources.GeneratePodDisruptionBudget(*validatingPodDeployment, overcommit.Spec.Deployment)
+	certificates.InjectCABundle(validatingPodWebhook, caBundle)
+	provider.Annotate(validatingPodService, validatingpodCertificate)
+	provider.Annotate(validatingPodWebhook, validatingpodCertificate)
+
+	if err := provider.Issue(ctx, overcommit, validatingpodCertificate); err != nil {
+		logger.Error(err, "Failed to issue the certificate of the pod validating webhook")
 		return ctrl.Result{}, err
 	}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingPodDeployment, func() error {
-		// Regenerate the desired deployment spec
-		updatedDeployment := resources.GeneratePodValidatingDeployment(*overcommit)
-		updatedDeployment.Spec.Template.Spec.Containers[0].Image = os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION")
-
-		// Only update if there are actual differences
-		if validatingPodDeployment.CreationTimestamp.IsZero() {
-			// New deployment, set everything
-			validatingPodDeployment.Spec = updatedDeployment.Spec
-			validatingPodDeployment.ObjectMeta.Labels = updatedDeployment.ObjectMeta.Labels
-			validatingPodDeployment.ObjectMeta.Annotations = updatedDeployment.ObjectMeta.Annotations
-			return ctrl.SetControllerReference(overcommit, validatingPodDeployment, r.Scheme)
-		} else {
-			// Existing deployment, only update specific fields if needed
-			updated := false
-			if updatedDeployment.Spec.Template.Spec.Containers[0].Image != validatingPodDeployment.Spec.Template.Spec.Containers[0].Image {
-				validatingPodDeployment.Spec.Template.Spec.Containers[0].Image = updatedDeployment.Spec.Template.Spec.Containers[0].Image
-				updated = true
-			}
-			// Update environment variables if they changed
-			if !envVarsEqual(updatedDeployment.Spec.Template.Spec.Containers[0].Env, validatingPodDeployment.Spec.Template.Spec.Containers[0].Env) {
-				validatingPodDeployment.Spec.Template.Spec.Containers[0].Env = updatedDeployment.Spec.Template.Spec.Containers[0].Env
-				updated = true
-			}
-			// Update template annotations if they changed
-			if !mapsEqual(updatedDeployment.Spec.Template.Annotations, validatingPodDeployment.Spec.Template.Annotations) {
-				validatingPodDeployment.Spec.Template.Annotations = updatedDeployment.Spec.Template.Annotations
-				updated = true
-			}
-			// Update template labels if they changed
-			if !mapsEqual(updatedDeployment.Spec.Template.Labels, validatingPodDeployment.Spec.Template.Labels) {
-				validatingPodDeployment.Spec.Template.Labels = updatedDeployment.Spec.Template.Labels
-				updated = true
-			}
-			// Only set controller reference if we actually updated something
-			if updated {
-				return ctrl.SetControllerReference(overcommit, validatingPodDeployment, r.Scheme)
-			}
-		}
-
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to reconcile Pod Validating Deployment")
-		return ctrl.Result{}, err
-	}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingPodService, func() error {
-		// Only update spec if this is a new resource
-		if validatingPodService.CreationTimestamp.IsZero() {
-			updatedService := resources.GeneratePodValidatingService(*validatingPodDeployment)
-			validatingPodService.Spec = updatedService.Spec
-			return ctrl.SetControllerReference(overcommit, validatingPodService, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to reconcile Pod Validating Service")
-		return ctrl.Result{}, err
-	}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingPodWebhook, func() error {
-		// Only update webhooks if this is a new resource
-		if validatingPodWebhook.CreationTimestamp.IsZero() {
-			updatedWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, label)
-			validatingPodWebhook.Webhooks = updatedWebhook.Webhooks
-			return ctrl.SetControllerReference(overcommit, validatingPodWebhook, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to reconcile Pod Validating Webhook")
-		// For resource conflicts, don't fail the reconciliation to avoid immediate retry
-		if errors.IsConflict(err) {
-			logger.Info("Resource conflict detected, will retry in next scheduled reconciliation")
-		} else {
+	for _, object := range []client.Object{validatingPodDeployment, validatingPodBudget, validatingPodService, validatingPodWebhook} {
+		if err := r.apply(ctx, overcommit, object); err != nil {
+			logger.Error(err, "Failed to reconcile the pod validating webhook")
 			return ctrl.Result{}, err
 		}
 	}
 
 	// Reconcile Overcommit Class Controller
-	occontroller := resources.GenerateOvercommitClassControllerDeployment(*overcommit)
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, occontroller, func() error {
-		// Regenerate the desired deployment spec
-		updatedDeployment := resources.GenerateOvercommitClassControllerDeployment(*overcommit)
-		updatedDeployment.Spec.Template.Spec.Containers[0].Image = os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION")
-
-		// Only update if there are actual differences
-		if occontroller.CreationTimestamp.IsZero() {
-			// New deployment, set everything
-			occontroller.Spec = updatedDeployment.Spec
-			occontroller.ObjectMeta.Labels = updatedDeployment.ObjectMeta.Labels
-			occontroller.ObjectMeta.Annotations = updatedDeployment.ObjectMeta.Annotations
-			logger.Info("Creating new OvercommitClass Controller deployment")
-			return ctrl.SetControllerReference(overcommit, occontroller, r.Scheme)
-		} else {
-			// Existing deployment, only update specific fields if needed
-			updated := false
-			if updatedDeployment.Spec.Template.Spec.Containers[0].Image != occontroller.Spec.Template.Spec.Containers[0].Image {
-				occontroller.Spec.Template.Spec.Containers[0].Image = updatedDeployment.Spec.Template.Spec.Containers[0].Image
-				updated = true
-			}
-			// Update environment variables if they changed
-			if !envVarsEqual(updatedDeployment.Spec.Template.Spec.Containers[0].Env, occontroller.Spec.Template.Spec.Containers[0].Env) {
-				occontroller.Spec.Template.Spec.Containers[0].Env = updatedDeployment.Spec.Template.Spec.Containers[0].Env
-				updated = true
-			}
-			// Update template annotations if they changed
-			if !mapsEqual(updatedDeployment.Spec.Template.Annotations, occontroller.Spec.Template.Annotations) {
-				occontroller.Spec.Template.Annotations = updatedDeployment.Spec.Template.Annotations
-				updated = true
-			}
-			// Update template labels if they changed
-			if !mapsEqual(updatedDeployment.Spec.Template.Labels, occontroller.Spec.Template.Labels) {
-				occontroller.Spec.Template.Labels = updatedDeployment.Spec.Template.Labels
-				updated = true
-			}
-			// Only set controller reference if we actually updated something
-			if updated {
-				return ctrl.SetControllerReference(overcommit, occontroller, r.Scheme)
-			}
-		}
+	occontroller := resources.GenerateOvercommitClassControllerDeployment(*overcommit, r.Config)
+	if err := r.apply(ctx, overcommit, occontroller); err != nil {
+		logger.Error(err, "Failed to reconcile the OvercommitClass controller")
+		return ctrl.Result{}, err
+	}
 
-		return nil
-	})
-	if err != nil {
+	if err := r.updateOvercommitStatusSafely(ctx); err != nil {
+		logger.Error(err, "Error updating resource status")
 		return ctrl.Result{}, err
 	}
 
-	// Only requeue periodically for status checks, not immediately
-	logger.Info("Reconciliation completed successfully", "nextReconcile", "10 seconds", "time", time.Now().Format("15:04:05"))
+	// The owned resources are watched, so the periodic requeue is only a safety net
+	logger.Info("Reconciliation completed successfully", "nextReconcile", r.ResyncPeriod.String(), "time", time.Now().Format("15:04:05"))
 	return ctrl.Result{
-		RequeueAfter: time.Second * 10,
+		RequeueAfter: utils.RequeueAfter(overcommit, r.ResyncPeriod),
 	}, nil
 }
 
 // +kubebuilder:rbac:groups=apps, resources=deployments;replicasets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="", resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="", resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy, resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io, resources=leases,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io, resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.k8s.io, resources=endpointslices,verbs=get;list;watch
 
 // SetupWithManager sets up the controller with the Manager.
+// The Overcommit resource is reconciled when its spec, its label migration or the resources it
+// owns change.
 func (r *OvercommitReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&overcommit.Overcommit{}).
+	controller := ctrl.NewControllerManagedBy(mgr)
+	// The cert-manager resources can only be watched when cert-manager is installed
+	if certificates.CertManagerInstalled(mgr.GetRESTMapper()) {
+		controller = controller.
+			Owns(&certmanagerv1.Certificate{}, builder.WithPredicates(events.CertificateRotations(r.Recorder, "Overcommit"))).
+			Owns(&certmanagerv1.Issuer{})
+	}
+	return controller.
+		For(&overcommit.Overcommit{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, utils.LabelMigrationChanged()))).
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(events.ImageUpgrades(r.Recorder, "Overcommit"))).
+		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&admissionv1.ValidatingWebhookConfiguration{}).
+		// The storage version is migrated once the CA bundle is injected in the OvercommitClass CRD
+		Watches(&apiextensionsv1.CustomResourceDefinition{},
+			handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "cluster"}}}
+			}),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+				return object.GetName() == resources.OvercommitClassCRDName
+			}))).
 		Named("Overcommit").
 		Complete(r)
 }
@@ -116,4 +116,68 @@ var _ = Describe("Overcommit Controller", func() {
 			Expect(k8sClient.Delete(ctx, overcommit)).Should(Succeed())
 		})
 	})
+
+	Context("When changing the overcommitLabel", func() {
+		It("Should honor both labels and report the migration", func() {
+			ctx := context.Background()
+			overcommit := &overcommitv1.Overcommit{
+				ObjectMeta: metav1.ObjectMeta{Name: OvercommitName},
+				Spec:       overcommitv1.OvercommitSpec{OvercommitLabel: "test-overcommit"},
+			}
+			// The Overcommit CR of the previous test may still be being deleted
+			Eventually(func() error {
+				return k8sClient.Create(ctx, overcommit)
+			}, timeout, interval).Should(Succeed())
+
+			By("Recording the label the resources are reconciled with")
+			Eventually(func() string {
+				_ = k8sClient.Get(ctx, client.ObjectKeyFromObject(overcommit), overcommit)
+				return overcommit.Status.OvercommitLabel
+			}, timeout, interval).Should(Equal("test-overcommit"))
+
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "label-migration",
+				Labels: map[string]string{"test-overcommit": "test-class"},
+			}}
+			Expect(k8sClient.Create(ctx, namespace)).Should(Succeed())
+
+			By("Changing the label")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(overcommit), overcommit); err != nil {
+					return err
+				}
+				overcommit.Spec.OvercommitLabel = "test-overcommit-new"
+				overcommit.Spec.LabelMigration = &overcommitv1.LabelMigrationSpec{NamespaceRelabel: overcommitv1.NamespaceRelabelDryRun}
+				return k8sClient.Update(ctx, overcommit)
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting the migration in the status")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(overcommit), overcommit)).To(Succeed())
+				migration := overcommit.Status.LabelMigration
+				g.Expect(migration).NotTo(BeNil())
+				g.Expect(migration.From).To(Equal("test-overcommit"))
+				g.Expect(migration.To).To(Equal("test-overcommit-new"))
+				g.Expect(migration.Phase).To(Equal(overcommitv1.LabelMigrationInProgress))
+				g.Expect(migration.NamespacesToRelabel).To(ContainElement("label-migration"))
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting the pod validating webhook to select both labels")
+			Eventually(func(g Gomega) {
+				webhook := &admissionv1.ValidatingWebhookConfiguration{}
+				g.Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "k8s-overcommit-pod-validating-webhook"}, webhook)).To(Succeed())
+				names := []string{}
+				for _, condition := range webhook.Webhooks[0].MatchConditions {
+					names = append(names, condition.Name)
+				}
+				g.Expect(names).To(ContainElement("overcommit-label"))
+			}, timeout, interval).Should(Succeed())
+
+			By("Leaving the namespace untouched in DryRun")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace)).To(Succeed())
+			Expect(namespace.Labels).To(HaveKey("test-overcommit"))
+
+			Expect(k8sClient.Delete(ctx, overcommit)).Should(Succeed())
+		})
+	})
 })
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
+	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcilePodMutatingWebhook creates or updates the pod mutating webhook Deployment,
+// PodDisruptionBudget, Service and Certificate shared by every class in the Consolidated topology. In the PerClass topology
+// they are deleted once no class points its MutatingWebhookConfiguration at them.
+func (r *OvercommitReconciler) reconcilePodMutatingWebhook(ctx context.Context, overcommitObject *overcommit.Overcommit, issuer certmanagerv1.Issuer, provider certificates.Provider) error {
+	deployment := resources.GeneratePodMutatingDeployment(*overcommitObject, r.Config)
+	budget := resources.GeneratePodDisruptionBudget(*deployment, overcommitObject.Spec.Deployment)
+	service := resources.GeneratePodMutatingService(*deployment)
+	certificate := resources.GenerateCertificateMutatingPods(issuer, *service)
+
+	if !overcommitObject.Consolidated() {
+		inUse, err := r.serviceInUse(ctx, *service)
+		if err != nil || inUse {
+			return err
+		}
+		for _, object := range []client.Object{deployment, budget, service, certificate} {
+			if err := certificates.IgnoreNotInstalled(r.Delete(ctx, object)); err != nil {
+				return fmt.Errorf("error deleting the shared pod mutating webhook %T: %w", object, err)
+			}
+		}
+		return nil
+	}
+
+	if err := provider.Issue(ctx, overcommitObject, certificate); err != nil {
+		return fmt.Errorf("error issuing the certificate of the shared pod mutating webhook: %w", err)
+	}
+	provider.Annotate(service, certificate)
+	for _, object := range []client.Object{deployment, budget, service} {
+		if err := r.apply(ctx, overcommitObject, object); err != nil {
+			return fmt.Errorf("error reconciling the shared pod mutating webhook: %w", err)
+		}
+	}
+	return nil
+}
+
+// serviceInUse reports whether a MutatingWebhookConfiguration still calls the service.
+func (r *OvercommitReconciler) serviceInUse(ctx context.Context, service corev1.Service) (bool, error) {
+	configurations := &admissionv1.MutatingWebhookConfigurationList{}
+	if err := r.List(ctx, configurations); err != nil {
+		return false, fmt.Errorf("error listing MutatingWebhookConfigurations: %w", err)
+	}
+	for _, configuration := range configurations.Items {
+		for _, webhook := range configuration.Webhooks {
+			if ref := webhook.ClientConfig.Service; ref != nil && ref.Name == service.Name && ref.Namespace == service.Namespace {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
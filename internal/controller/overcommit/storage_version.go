@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+
+	overcommitv1 "github.com/InditexTech/k8s-overcommit-operator/api/v1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
+	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=update;patch,resourceNames=overcommitclasses.overcommit.inditex.dev
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=get;update;patch,resourceNames=overcommitclasses.overcommit.inditex.dev
+
+// reconcileConversion points the conversion of the OvercommitClass CRD at the OvercommitClass
+// validating webhook. Once the webhook converts, it migrates the stored OvercommitClasses to
+// v1, the storage version of the installed CRD: every class is rewritten and v1alphav1 is
+// dropped from the stored versions. The CA bundle is injected by cert-manager or the service CA when caBundle
+// is nil.
+func (r *OvercommitReconciler) reconcileConversion(ctx context.Context, deployment *appsv1.Deployment, service corev1.Service, certificate certmanagerv1.Certificate, provider certificates.Provider, caBundle []byte) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := r.Get(ctx, client.ObjectKey{Name: resources.OvercommitClassCRDName}, crd); err != nil {
+		return fmt.Errorf("error getting the OvercommitClass CRD: %w", err)
+	}
+
+	conversion, annotations := resources.GenerateOvercommitClassConversion(service, certificate)
+	if caBundle != nil {
+		annotations = nil
+		conversion.Webhook.ClientConfig.CABundle = caBundle
+	}
+	desired := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	provider.Annotate(desired, &certificate)
+
+	patch := client.MergeFrom(crd.DeepCopy())
+	changed := false
+	for key, value := range desired.Annotations {
+		if crd.Annotations[key] != value {
+			if crd.Annotations == nil {
+				crd.Annotations = map[string]string{}
+			}
+			crd.Annotations[key] = value
+			changed = true
+		}
+	}
+	// Stop the injection of the CA bundle by the previous provider
+	for _, key := range certificates.CAInjectionAnnotations {
+		if _, desiredKey := desired.Annotations[key]; !desiredKey {
+			if _, ok := crd.Annotations[key]; ok {
+				delete(crd.Annotations, key)
+				changed = true
+			}
+		}
+	}
+	if current := crd.Spec.Conversion; current == nil || current.Webhook == nil || current.Webhook.ClientConfig == nil ||
+		current.Strategy != conversion.Strategy ||
+		!reflect.DeepEqual(current.Webhook.ClientConfig.Service, conversion.Webhook.ClientConfig.Service) ||
+		!reflect.DeepEqual(current.Webhook.ConversionReviewVersions, conversion.Webhook.ConversionReviewVersions) ||
+		(caBundle != nil && !bytes.Equal(current.Webhook.ClientConfig.CABundle, caBundle)) {
+		// Keep the CA bundle injected by cert-manager or the service CA
+		if current != nil && current.Webhook != nil && current.Webhook.ClientConfig != nil && caBundle == nil {
+			conversion.Webhook.ClientConfig.CABundle = current.Webhook.ClientConfig.CABundle
+		}
+		crd.Spec.Conversion = conversion
+		changed = true
+	}
+	if changed {
+		logger.Info("Setting the OvercommitClass conversion webhook", "service", service.Name)
+		if err := r.Patch(ctx, crd, patch); err != nil {
+			return fmt.Errorf("error setting the OvercommitClass conversion webhook: %w", err)
+		}
+		return nil
+	}
+
+	if len(crd.Spec.Conversion.Webhook.ClientConfig.CABundle) == 0 || !deploymentAvailable(deployment) {
+		logger.Info("Waiting for the OvercommitClass conversion webhook before migrating the storage version")
+		return nil
+	}
+	return r.migrateStorageVersion(ctx, crd)
+}
+
+// migrateStorageVersion rewrites every OvercommitClass in v1 and drops the previous versions
+// from the stored versions of the CRD. The storage version is set by the installed CRD, the
+// migration waits while it isn't v1.
+func (r *OvercommitReconciler) migrateStorageVersion(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) error {
+	storage := overcommitv1.GroupVersion.Version
+	if current := storageVersion(crd); current != storage {
+		logger.Info("Waiting for the OvercommitClass CRD to be upgraded before migrating the storage version", "storageVersion", current)
+		return nil
+	}
+	if reflect.DeepEqual(crd.Status.StoredVersions, []string{storage}) {
+		return nil
+	}
+
+	// An update without changes is enough for the API server to write the class in the
+	// storage version
+	classes := &overcommitv1.OvercommitClassList{}
+	if err := r.List(ctx, classes); err != nil {
+		return fmt.Errorf("error listing OvercommitClasses: %w", err)
+	}
+	for i := range classes.Items {
+		if err := r.Update(ctx, &classes.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("error migrating OvercommitClass %s: %w", classes.Items[i].Name, err)
+		}
+	}
+
+	crd.Status.StoredVersions = []string{storage}
+	if err := r.Status().Update(ctx, crd); err != nil {
+		return fmt.Errorf("error updating the OvercommitClass stored versions: %w", err)
+	}
+	logger.Info("OvercommitClass storage version migrated", "version", storage, "classes", len(classes.Items))
+	return nil
+}
+
+// storageVersion returns the storage version of the CRD.
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// deploymentAvailable reports whether every replica of the deployment is ready.
+func deploymentAvailable(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ReadyReplicas >= replicas && deployment.Status.ReadyReplicas > 0
+}
@@ -11,145 +11,124 @@ import (
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/apply"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/conditions"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/events"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/readiness"
 	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
-	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (r *OvercommitReconciler) updateOvercommitStatus(ctx context.Context, overcommitObject *overcommit.Overcommit) error {
-	logger := logf.FromContext(ctx)
-
-	// Initialize resource status map
-	resourceStatuses := make(map[string]overcommit.ResourceStatus)
-
-	// Check Issuer status
-	issuer := resources.GenerateIssuer()
-	err := r.Get(ctx, client.ObjectKey{Name: issuer.Name, Namespace: issuer.Namespace}, issuer)
-	if err == nil {
-		resourceStatuses["issuer"] = overcommit.ResourceStatus{Name: issuer.Name, Ready: true}
-	} else {
-		resourceStatuses["issuer"] = overcommit.ResourceStatus{Name: issuer.Name, Ready: false}
-	}
+// apply applies the generated object with the Overcommit resource as its controller.
+func (r *OvercommitReconciler) apply(ctx context.Context, owner client.Object, object client.Object) error {
+	return apply.Applier{Client: r.Client, Scheme: r.Scheme, Recorder: r.Recorder}.Apply(ctx, owner, object)
+}
 
-	// Check Deployment Validating Class status
-	deployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommitObject)
-	err = r.Get(ctx, client.ObjectKey{Name: deployment.Name, Namespace: deployment.Namespace}, deployment)
-	if err == nil {
-		resourceStatuses["deployment"] = overcommit.ResourceStatus{Name: deployment.Name, Ready: true}
-	} else {
-		resourceStatuses["deployment"] = overcommit.ResourceStatus{Name: deployment.Name, Ready: false}
+// reader returns the reader of the objects that are not cached: the APIReader, or the client when
+// it is not set.
+func (r *OvercommitReconciler) reader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
 	}
+	return r.APIReader
+}
 
-	// Check Service Validating Class status
-	service := resources.GenerateOvercommitClassValidatingService(*deployment)
-	err = r.Get(ctx, client.ObjectKey{Name: service.Name, Namespace: service.Namespace}, service)
-	if err == nil {
-		resourceStatuses["service"] = overcommit.ResourceStatus{Name: service.Name, Ready: true}
-	} else {
-		resourceStatuses["service"] = overcommit.ResourceStatus{Name: service.Name, Ready: false}
-	}
+// certificateProvider returns the provider of the certificates of the Overcommit resource.
+func (r *OvercommitReconciler) certificateProvider(overcommitObject *overcommit.Overcommit) certificates.Provider {
+	return certificates.New(overcommitObject, certificates.Options{Namespace: r.Config.Namespace, Client: r.Client, Reader: r.APIReader, Scheme: r.Scheme, Recorder: r.Recorder})
+}
 
-	// Check Certificate Validating Class status
-	certificate := resources.GenerateCertificateValidatingOvercommitClass(*resources.GenerateIssuer(), *service)
-	err = r.Get(ctx, client.ObjectKey{Name: certificate.Name, Namespace: certificate.Namespace}, certificate)
-	if err == nil {
-		resourceStatuses["certificate"] = overcommit.ResourceStatus{Name: certificate.Name, Ready: true}
-	} else {
-		resourceStatuses["certificate"] = overcommit.ResourceStatus{Name: certificate.Name, Ready: false}
+// updateOvercommitStatus records in the status of the Overcommit resource whether the resources
+// generated for it work.
+func (r *OvercommitReconciler) updateOvercommitStatus(ctx context.Context, overcommitObject *overcommit.Overcommit) error {
+	migration := overcommitObject.Status.LabelMigration.DeepCopy()
+	if err := r.updateLabelMigration(ctx, overcommitObject, time.Now()); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update the label migration")
+		return err
 	}
+	labels := overcommitObject.OvercommitLabels(time.Now())
 
-	// Check Webhook Validating Class status
+	issuer := resources.GenerateIssuer(r.Config.Namespace)
+	deployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommitObject, r.Config)
+	service := resources.GenerateOvercommitClassValidatingService(*deployment)
+	certificate := resources.GenerateCertificateValidatingOvercommitClass(*issuer, *service)
 	webhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*deployment, *service, *certificate)
-	err = r.Get(ctx, client.ObjectKey{Name: webhook.Name}, webhook)
-	if err == nil {
-		resourceStatuses["webhook"] = overcommit.ResourceStatus{Name: webhook.Name, Ready: true}
-	} else {
-		resourceStatuses["webhook"] = overcommit.ResourceStatus{Name: webhook.Name, Ready: false}
-	}
-
-	// Check Deployment Validating Pods status
-	podDeployment := resources.GeneratePodValidatingDeployment(*overcommitObject)
-	err = r.Get(ctx, client.ObjectKey{Name: podDeployment.Name, Namespace: podDeployment.Namespace}, podDeployment)
-	if err == nil {
-		resourceStatuses["podDeployment"] = overcommit.ResourceStatus{Name: podDeployment.Name, Ready: true}
-	} else {
-		resourceStatuses["podDeployment"] = overcommit.ResourceStatus{Name: podDeployment.Name, Ready: false}
-	}
-
-	// Check Service Validating Pods status
+	podDeployment := resources.GeneratePodValidatingDeployment(*overcommitObject, r.Config)
 	podService := resources.GeneratePodValidatingService(*podDeployment)
-	err = r.Get(ctx, client.ObjectKey{Name: podService.Name, Namespace: podService.Namespace}, podService)
-	if err == nil {
-		resourceStatuses["podService"] = overcommit.ResourceStatus{Name: podService.Name, Ready: true}
-	} else {
-		resourceStatuses["podService"] = overcommit.ResourceStatus{Name: podService.Name, Ready: false}
-	}
-
-	// Check Certificate Validating Pods status
-	podCertificate := resources.GenerateCertificateValidatingPods(*resources.GenerateIssuer(), *podService)
-	err = r.Get(ctx, client.ObjectKey{Name: podCertificate.Name, Namespace: podCertificate.Namespace}, podCertificate)
-	if err == nil {
-		resourceStatuses["podCertificate"] = overcommit.ResourceStatus{Name: podCertificate.Name, Ready: true}
-	} else {
-		resourceStatuses["podCertificate"] = overcommit.ResourceStatus{Name: podCertificate.Name, Ready: false}
-	}
-
-	// Check Webhook Validating Pods status
-	label, err := utils.GetOvercommitLabel(ctx, r.Client)
-	if err != nil {
-		logger.Error(err, "Failed to get Overcommit label")
-		return err
-	}
-	podWebhook := resources.GeneratePodValidatingWebhookConfiguration(*podDeployment, *podService, *podCertificate, label)
-	err = r.Get(ctx, client.ObjectKey{Name: podWebhook.Name}, podWebhook)
-	if err == nil {
-		resourceStatuses["podWebhook"] = overcommit.ResourceStatus{Name: podWebhook.Name, Ready: true}
-	} else {
-		resourceStatuses["podWebhook"] = overcommit.ResourceStatus{Name: podWebhook.Name, Ready: false}
-	}
-
-	// Convert map to slice for CRD status
-	resourceStatusSlice := make([]overcommit.ResourceStatus, 0, len(resourceStatuses)) // Pre-allocate slice
-	allReady := true
-	for _, status := range resourceStatuses {
-		resourceStatusSlice = append(resourceStatusSlice, status)
-		if !status.Ready {
-			allReady = false
-		}
-	}
-
-	// Update the status of the CRD
-	overcommitObject.Status.Resources = resourceStatusSlice
-
-	// Update the condition
-	condition := metav1.Condition{
-		Type:    "ResourcesReady",
-		Status:  metav1.ConditionTrue,
-		Reason:  "AllResourcesReady",
-		Message: "All managed resources are ready",
-	}
-	if !allReady {
-		condition.Status = metav1.ConditionFalse
-		condition.Reason = "ResourcesNotReady"
-		condition.Message = "Some resources are not ready"
-	}
-	setCondition(&overcommitObject.Status, condition)
+	podCertificate := resources.GenerateCertificateValidatingPods(*issuer, *podService)
+	podWebhook := resources.GeneratePodValidatingWebhookConfiguration(*podDeployment, *podService, *podCertificate, labels)
+	controllerDeployment := resources.GenerateOvercommitClassControllerDeployment(*overcommitObject, r.Config)
+
+	deployments := []*appsv1.Deployment{deployment, podDeployment, controllerDeployment}
+	services := []*corev1.Service{service, podService}
+	servingCertificates := []*certmanagerv1.Certificate{certificate, podCertificate}
+	if overcommitObject.Consolidated() {
+		mutatingDeployment := resources.GeneratePodMutatingDeployment(*overcommitObject, r.Config)
+		mutatingService := resources.GeneratePodMutatingService(*mutatingDeployment)
+		deployments = append(deployments, mutatingDeployment)
+		services = append(services, mutatingService)
+		servingCertificates = append(servingCertificates, resources.GenerateCertificateMutatingPods(*issuer, *mutatingService))
+	}
+
+	provider := r.certificateProvider(overcommitObject)
+	statuses := []overcommit.ResourceStatus{}
+	for _, status := range provider.Authority(ctx) {
+		statuses = append(statuses, resourceStatus(status.Kind, status.Name, status.Result))
+	}
+	for _, deployment := range deployments {
+		statuses = append(statuses, resourceStatus("Deployment", deployment.Name,
+			readiness.Check(ctx, r.Client, "Deployment", client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}, readiness.Deployment)))
+	}
+	for _, service := range services {
+		statuses = append(statuses, resourceStatus("Service", service.Name,
+			readiness.Check(ctx, r.Client, "Service", client.ObjectKeyFromObject(service), &corev1.Service{}, readiness.Service)))
+	}
+	for _, certificate := range servingCertificates {
+		status := provider.Check(ctx, certificate)
+		statuses = append(statuses, resourceStatus(status.Kind, status.Name, status.Result))
+	}
+	for _, webhook := range []*admissionv1.ValidatingWebhookConfiguration{webhook, podWebhook} {
+		statuses = append(statuses, resourceStatus("ValidatingWebhookConfiguration", webhook.Name,
+			readiness.Check(ctx, r.Client, "ValidatingWebhookConfiguration", client.ObjectKey{Name: webhook.Name}, &admissionv1.ValidatingWebhookConfiguration{},
+				func(configuration *admissionv1.ValidatingWebhookConfiguration) readiness.Result {
+					return readiness.ValidatingWebhookConfiguration(ctx, r.reader(), configuration)
+				})))
+	}
+
+	overcommitObject.Status.ObservedGeneration = overcommitObject.Generation
+	overcommitObject.Status.Resources = statuses
+
+	// Conditions
+	reachable := meta.FindStatusCondition(overcommitObject.Status.Conditions, overcommit.ConditionWebhookReachable).DeepCopy()
+	conditions.Set(&overcommitObject.Status.Conditions, statuses, overcommitObject.Generation)
+	events.WebhookReachability(r.Recorder, overcommitObject, reachable,
+		meta.FindStatusCondition(overcommitObject.Status.Conditions, overcommit.ConditionWebhookReachable))
+	events.LabelMigration(r.Recorder, overcommitObject, migration, overcommitObject.Status.LabelMigration)
 
 	// Update the status in the API
 	if err := r.Status().Update(ctx, overcommitObject); err != nil {
-		logger.Error(err, "Failed to update Overcommit status")
+		logf.FromContext(ctx).Error(err, "Failed to update Overcommit status")
 		return err
 	}
 
 	return nil
 }
 
+// resourceStatus is the status of a resource with the given readiness.
+func resourceStatus(kind, name string, result readiness.Result) overcommit.ResourceStatus {
+	return overcommit.ResourceStatus{Name: name, Kind: kind, Ready: result.Ready, Reason: result.Reason, Message: result.Message}
+}
+
 // updateOvercommitStatusSafely safely updates the status by first refreshing the object from the cluster
 // with retry logic to handle concurrent modifications
 // Since Overcommit is cluster-wide and always named "cluster", we use a fixed key
@@ -189,127 +168,71 @@ func (r *OvercommitReconciler) updateOvercommitStatusSafely(ctx context.Context)
 	return fmt.Errorf("failed to update status after 3 attempts")
 }
 
-func setCondition(status *overcommit.OvercommitStatus, newCondition metav1.Condition) {
-	for i, existingCondition := range status.Conditions {
-		if existingCondition.Type == newCondition.Type {
-			if existingCondition.Status != newCondition.Status ||
-				existingCondition.Reason != newCondition.Reason ||
-				existingCondition.Message != newCondition.Message {
-				newCondition.LastTransitionTime = metav1.Now()
-				status.Conditions[i] = newCondition
-			}
-			return
-		}
-	}
-	newCondition.LastTransitionTime = metav1.Now()
-	status.Conditions = append(status.Conditions, newCondition)
-}
-
 // cleanupResources ensures that all resources associated with the CR are deleted.
 func (r *OvercommitReconciler) cleanupResources(ctx context.Context, overcommitObject *overcommit.Overcommit) error {
 	logger := logf.FromContext(ctx)
 	logger.Info("Cleaning up resources associated with Overcommit CR")
 
-	label, err := utils.GetOvercommitLabel(ctx, r.Client)
-	if err != nil {
-		logger.Error(err, "Failed to get Overcommit label")
-		return err
-	}
-
-	// Delete Issuer
-	issuer := resources.GenerateIssuer()
+	// Delete Issuer, cert-manager may not be installed
+	issuer := resources.GenerateIssuer(r.Config.Namespace)
 	if issuer != nil {
 		err := r.Delete(ctx, issuer)
-		if err != nil && client.IgnoreNotFound(err) != nil {
+		if certificates.IgnoreNotInstalled(err) != nil {
 			logger.Error(err, "Failed to delete Issuer")
 			return err
 		}
 	}
 
 	// Delete OvercommitClassValidator resources
-	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommitObject)
+	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommitObject, r.Config)
 	overcommitClassService := resources.GenerateOvercommitClassValidatingService(*overcommitClassDeployment)
 	overcommitClassCertificate := resources.GenerateCertificateValidatingOvercommitClass(*issuer, *overcommitClassService)
 	overcommitClassWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate)
 
-	for _, resource := range []client.Object{overcommitClassDeployment, overcommitClassService, overcommitClassCertificate, overcommitClassWebhook} {
+	overcommitClassBudget := resources.GeneratePodDisruptionBudget(*overcommitClassDeployment, nil)
+
+	for _, resource := range []client.Object{overcommitClassDeployment, overcommitClassBudget, overcommitClassService, overcommitClassCertificate, overcommitClassWebhook} {
 		err := r.Delete(ctx, resource)
-		if err != nil && client.IgnoreNotFound(err) != nil {
+		if certificates.IgnoreNotInstalled(err) != nil {
 			logger.Error(err, fmt.Sprintf("Failed to delete resource: %T", resource))
 			return err
 		}
 	}
 
 	// Delete PodValidator resources
-	validatingPodDeployment := resources.GeneratePodValidatingDeployment(*overcommitObject)
+	validatingPodDeployment := resources.GeneratePodValidatingDeployment(*overcommitObject, r.Config)
 	validatingPodService := resources.GeneratePodValidatingService(*validatingPodDeployment)
 	validatingpodCertificate := resources.GenerateCertificateValidatingPods(*issuer, *validatingPodService)
-	validatingPodWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, label)
+	validatingPodWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, overcommitObject.OvercommitLabels(time.Now()))
+
+	validatingPodBudget := resources.GeneratePodDisruptionBudget(*validatingPodDeployment, nil)
 
-	for _, resource := range []client.Object{validatingPodDeployment, validatingPodService, validatingpodCertificate, validatingPodWebhook} {
+	for _, resource := range []client.Object{validatingPodDeployment, validatingPodBudget, validatingPodService, validatingpodCertificate, validatingPodWebhook} {
 		err := r.Delete(ctx, resource)
-		if err != nil && client.IgnoreNotFound(err) != nil {
+		if certificates.IgnoreNotInstalled(err) != nil {
 			logger.Error(err, fmt.Sprintf("Failed to delete resource: %T", resource))
 			return err
 		}
 	}
 
-	occontroller := resources.GenerateOvercommitClassControllerDeployment(*overcommitObject)
-	err = r.Delete(ctx, occontroller)
-	if err != nil && client.IgnoreNotFound(err) != nil {
-		logger.Error(err, "Failed to delete Overcommit Class Controller")
-	}
-	return nil
-}
-
-// envVarsEqual compares two slices of environment variables to see if they're equal
-// rsEqual compares two slices of environment variables to see if they're equal
-func envVarsEqual(a, b []corev1.EnvVar) bool {
-	if len(a) != len(b) {
-		return false
-	}
-
-	// Create maps for easier comparison
-	mapA := make(map[string]string)
-	mapB := make(map[string]string)
-
-	for _, env := range a {
-		mapA[env.Name] = env.Value
-	}
-
-	for _, env := range b {
-		mapB[env.Name] = env.Value
-	}
+	// Delete the shared pod mutating webhook resources
+	mutatingPodDeployment := resources.GeneratePodMutatingDeployment(*overcommitObject, r.Config)
+	mutatingPodService := resources.GeneratePodMutatingService(*mutatingPodDeployment)
+	mutatingPodCertificate := resources.GenerateCertificateMutatingPods(*issuer, *mutatingPodService)
+	mutatingPodBudget := resources.GeneratePodDisruptionBudget(*mutatingPodDeployment, nil)
 
-	// Compare maps
-	for key, valueA := range mapA {
-		if valueB, exists := mapB[key]; !exists || valueA != valueB {
-			return false
+	for _, resource := range []client.Object{mutatingPodDeployment, mutatingPodBudget, mutatingPodService, mutatingPodCertificate} {
+		err := r.Delete(ctx, resource)
+		if certificates.IgnoreNotInstalled(err) != nil {
+			logger.Error(err, fmt.Sprintf("Failed to delete resource: %T", resource))
+			return err
 		}
 	}
 
-	return true
-}
-
-// annotationsEqual compares two annotation maps to see if they're equal
-func mapsEqual(a, b map[string]string) bool {
-	// Handle nil cases
-	if a == nil && b == nil {
-		return true
-	}
-	if a == nil || b == nil {
-		return false
-	}
-
-	if len(a) != len(b) {
-		return false
-	}
-
-	for key, valueA := range a {
-		if valueB, exists := b[key]; !exists || valueA != valueB {
-			return false
-		}
+	occontroller := resources.GenerateOvercommitClassControllerDeployment(*overcommitObject, r.Config)
+	err := r.Delete(ctx, occontroller)
+	if certificates.IgnoreNotInstalled(err) != nil {
+		logger.Error(err, "Failed to delete Overcommit Class Controller")
 	}
-
-	return true
+	return nil
 }
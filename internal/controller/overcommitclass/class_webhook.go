@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	certmanager "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reconcileClassWebhook creates or updates the webhook Deployment, PodDisruptionBudget, Service
+// and Certificate of a class in the PerClass topology.
+func (r *OvercommitClassReconciler) reconcileClassWebhook(ctx context.Context, overcommitClass *overcommit.OvercommitClass, overcommitResource overcommit.Overcommit, provider certificates.Provider) (*appsv1.Deployment, *corev1.Service, *certmanager.Certificate, error) {
+	logger := log.FromContext(ctx)
+
+	template := resources.MergeDeploymentTemplates(overcommitResource.Spec.Deployment, overcommitClass.Spec.Deployment)
+	deployment := resources.CreateDeployment(*overcommitClass, overcommitResource.Spec.Deployment, r.Config)
+	budget := resources.GeneratePodDisruptionBudget(*deployment, template)
+	service := resources.CreateService(overcommitClass.Name, r.Config.Namespace)
+	certificate := resources.CreateCertificate(overcommitClass.Name, *service)
+
+	if err := provider.Issue(ctx, overcommitClass, certificate); err != nil {
+		logger.Error(err, "Failed to issue the certificate of the class")
+		return nil, nil, nil, err
+	}
+	provider.Annotate(service, certificate)
+	for _, object := range []client.Object{deployment, budget, service} {
+		if err := r.apply(ctx, overcommitClass, object); err != nil {
+			logger.Error(err, "Failed to apply the webhook resources of the class")
+			return nil, nil, nil, err
+		}
+	}
+
+	return deployment, service, certificate, nil
+}
+
+// sharedWebhookAvailable reports whether every replica of the pod mutating webhook deployment
+// shared by the classes in the Consolidated topology is ready.
+func (r *OvercommitClassReconciler) sharedWebhookAvailable(ctx context.Context, overcommitResource overcommit.Overcommit) bool {
+	deployment := resources.GeneratePodMutatingDeployment(overcommitResource, r.Config)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		log.FromContext(ctx).Info("Waiting for the shared pod mutating webhook", "deployment", deployment.Name)
+		return false
+	}
+	available := deployment.Spec.Replicas != nil && deployment.Status.ReadyReplicas >= *deployment.Spec.Replicas && deployment.Status.ReadyReplicas > 0
+	if !available {
+		log.FromContext(ctx).Info("Waiting for the shared pod mutating webhook", "deployment", deployment.Name, "readyReplicas", deployment.Status.ReadyReplicas)
+	}
+	return available
+}
+
+// deleteClassWebhook deletes the webhook Deployment, PodDisruptionBudget, Service and Certificate
+// of a class in the PerClass topology.
+func deleteClassWebhook(ctx context.Context, c client.Client, cfg config.Config, name string) error {
+	deployment := resources.CreateDeployment(overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil, cfg)
+	service := resources.CreateService(name, cfg.Namespace)
+	objects := []client.Object{
+		deployment,
+		resources.GeneratePodDisruptionBudget(*deployment, nil),
+		service,
+		resources.CreateCertificate(name, *service),
+	}
+	for _, object := range objects {
+		if err := ensureResourceDeleted(ctx, c, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
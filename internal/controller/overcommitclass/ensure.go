@@ -8,14 +8,23 @@ package controller
 import (
 	"context"
 
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/apply"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// certificateProvider returns the provider of the certificates of the Overcommit resource.
+func (r *OvercommitClassReconciler) certificateProvider(overcommitResource *overcommit.Overcommit) certificates.Provider {
+	return certificates.New(overcommitResource, certificates.Options{Namespace: r.Config.Namespace, Client: r.Client, Reader: r.APIReader, Scheme: r.Scheme, Recorder: r.Recorder})
+}
+
+// apply applies the object with server-side apply on behalf of the owner, see apply.Applier.
+func (r *OvercommitClassReconciler) apply(ctx context.Context, owner client.Object, object client.Object) error {
+	return apply.Applier{Client: r.Client, Scheme: r.Scheme, Recorder: r.Recorder}.Apply(ctx, owner, object)
+}
+
 func ensureResourceDeleted(ctx context.Context, c client.Client, obj client.Object) error {
-	err := c.Delete(ctx, obj)
-	if err != nil && !apierrors.IsNotFound(err) {
-		return err
-	}
-	return nil
+	// The Certificates can't be deleted when cert-manager isn't installed
+	return certificates.IgnoreNotInstalled(c.Delete(ctx, obj))
 }
@@ -11,32 +11,53 @@ import (
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certificates"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/events"
 	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 	certmanager "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // OvercommitClassReconciler reconciles a OvercommitClass object
 type OvercommitClassReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder records the lifecycle events of the generated resources on the class
+	Recorder record.EventRecorder
+	// ResyncPeriod requeues every class periodically as a safety net for missed events.
+	// Zero disables it.
+	ResyncPeriod time.Duration
+	// APIReader reads the secrets of the certificates and the endpoints of the webhooks without
+	// caching them. The client is used when it is not set.
+	APIReader client.Reader
+	// Config is the configuration of the operator the webhooks are generated with.
+	Config config.Config
 }
 
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses/finalizers,verbs=update
-// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -49,40 +70,61 @@ type OvercommitClassReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/reconcile
 
 // SetupWithManager sets up the controller with the Manager.
+// The class is reconciled when its spec or the resources it owns change, and every class is
+// reconciled when the spec or the label migration of the Overcommit resource, or the shared pod
+// mutating webhook deployment change.
 func (r *OvercommitClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&overcommit.OvercommitClass{}).
+	controller := ctrl.NewControllerManagedBy(mgr)
+	// The cert-manager resources can only be watched when cert-manager is installed
+	if certificates.CertManagerInstalled(mgr.GetRESTMapper()) {
+		controller = controller.
+			Owns(&certmanager.Certificate{}, builder.WithPredicates(events.CertificateRotations(r.Recorder, "OvercommitClass")))
+	}
+	return controller.
+		For(&overcommit.OvercommitClass{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(events.ImageUpgrades(r.Recorder, "OvercommitClass"))).
+		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&admissionv1.MutatingWebhookConfiguration{}).
+		Watches(&overcommit.Overcommit{},
+			handler.EnqueueRequestsFromMapFunc(r.allClasses),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, utils.LabelMigrationChanged()))).
+		Watches(&appsv1.Deployment{},
+			handler.EnqueueRequestsFromMapFunc(r.allClasses),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+				return object.GetName() == resources.PodMutatingWebhookName
+			}))).
 		Named("OvercommitClass").
 		Complete(r)
 }
 
+// allClasses maps an event to a request for every OvercommitClass.
+func (r *OvercommitClassReconciler) allClasses(ctx context.Context, _ client.Object) []reconcile.Request {
+	classes := &overcommit.OvercommitClassList{}
+	if err := r.List(ctx, classes); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list OvercommitClasses")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(classes.Items))
+	for _, class := range classes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&class)})
+	}
+	return requests
+}
+
 func (r *OvercommitClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	label, err := utils.GetOvercommitLabel(ctx, r.Client)
-	if err != nil {
-		logger.Error(err, "Failed to get Overcommit label")
-		return ctrl.Result{}, err
-	}
-
 	overcommitClass := &overcommit.OvercommitClass{}
 
-	err = r.Get(ctx, req.NamespacedName, overcommitClass)
+	err := r.Get(ctx, req.NamespacedName, overcommitClass)
 	if err != nil {
 		logger.Info("Deleting resources for the class", "name", req.Name)
-		err := ensureResourceDeleted(ctx, r.Client, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: req.Name + "-webhook-deployment", Namespace: "k8s-overcommit"}})
-		if err != nil {
-			logger.Error(err, "Failed to delete Deployment")
-		}
-		err = ensureResourceDeleted(ctx, r.Client, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: req.Name + "-webhook-service", Namespace: "k8s-overcommit"}})
-		if err != nil {
-			logger.Error(err, "Failed to delete Service")
-		}
-		err = ensureResourceDeleted(ctx, r.Client, &certmanager.Certificate{ObjectMeta: metav1.ObjectMeta{Name: req.Name + "-webhook-certificate", Namespace: "k8s-overcommit"}})
+		err := deleteClassWebhook(ctx, r.Client, r.Config, req.Name)
 		if err != nil {
-			logger.Error(err, "Failed to delete Certificate")
+			logger.Error(err, "Failed to delete the webhook resources of the class")
 		}
-		err = ensureResourceDeleted(ctx, r.Client, &admissionv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: req.Name + "overcommit-webhook", Namespace: "k8s-overcommit"}})
+		err = ensureResourceDeleted(ctx, r.Client, &admissionv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: req.Name + "-overcommit-webhook"}})
 		if err != nil {
 			logger.Error(err, "Failed to delete MutatingWebhookConfiguration")
 		}
@@ -130,58 +172,53 @@ func (r *OvercommitClassReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return ctrl.Result{}, err
 		}
 		logger.Info("ControllerReference updated, requeuing reconciliation")
-		return ctrl.Result{}, nil
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	logger.Info("Reconciling resources for the class", "name", overcommitClass)
-	deployment := resources.CreateDeployment(*overcommitClass)
-	service := resources.CreateService(overcommitClass.Name)
-	certificate := resources.CreateCertificate(overcommitClass.Name, *service)
-	webhookConfig := resources.CreateMutatingWebhookConfiguration(*overcommitClass, *service, *certificate, label)
-
-	// Use CreateOrUpdate for each resource
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
-		// Update the deployment spec if needed
-		updatedDeployment := resources.CreateDeployment(*overcommitClass)
-		deployment.Spec = updatedDeployment.Spec
-		return controllerutil.SetControllerReference(overcommitClass, deployment, r.Scheme)
-	})
+	provider := r.certificateProvider(&overcommitResource)
+	caBundle, err := provider.CABundle(ctx)
 	if err != nil {
-		logger.Error(err, "Failed to create or update Deployment")
+		logger.Error(err, "Failed to get the CA bundle")
 		return ctrl.Result{}, err
 	}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
-		// Update the service spec if needed
-		updatedService := resources.CreateService(overcommitClass.Name)
-		service.Spec = updatedService.Spec
-		return controllerutil.SetControllerReference(overcommitClass, service, r.Scheme)
-	})
-	if err != nil {
-		logger.Error(err, "Failed to create or update Service")
-		return ctrl.Result{}, err
+	var (
+		deployment  *appsv1.Deployment
+		service     *corev1.Service
+		certificate *certmanager.Certificate
+		path        = resources.PodMutatingWebhookPath
+	)
+	// The class keeps its own deployment until the shared one can serve it
+	consolidated := overcommitResource.Consolidated() && r.sharedWebhookAvailable(ctx, overcommitResource)
+	if consolidated {
+		// The shared webhook deployment is reconciled by the Overcommit controller, the class
+		// only points its MutatingWebhookConfiguration at it
+		deployment = resources.GeneratePodMutatingDeployment(overcommitResource, r.Config)
+		service = resources.GeneratePodMutatingService(*deployment)
+		certificate = resources.GenerateCertificateMutatingPods(*resources.GenerateIssuer(r.Config.Namespace), *service)
+		path = resources.ClassWebhookPath(overcommitClass.Name)
+	} else {
+		deployment, service, certificate, err = r.reconcileClassWebhook(ctx, overcommitClass, overcommitResource, provider)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, certificate, func() error {
-		// Update the certificate spec if needed
-		updatedCertificate := resources.CreateCertificate(overcommitClass.Name, *service)
-		certificate.Spec = updatedCertificate.Spec
-		return controllerutil.SetControllerReference(overcommitClass, certificate, r.Scheme)
-	})
-	if err != nil {
-		logger.Error(err, "Failed to create or update Certificate")
+	webhookConfig := resources.CreateMutatingWebhookConfiguration(*overcommitClass, *service, *certificate, overcommitResource.OvercommitLabels(time.Now()), path)
+	certificates.InjectCABundle(webhookConfig, caBundle)
+	provider.Annotate(webhookConfig, certificate)
+	// The CA bundles injected by cert-manager are kept, as the applied configuration doesn't set them
+	if err := r.apply(ctx, overcommitClass, webhookConfig); err != nil {
+		logger.Error(err, "Failed to apply MutatingWebhookConfiguration")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, webhookConfig, func() error {
-		// Update the webhook configuration spec if needed
-		updatedWebhookConfig := resources.CreateMutatingWebhookConfiguration(*overcommitClass, *service, *certificate, label)
-		webhookConfig.Webhooks = updatedWebhookConfig.Webhooks
-		return controllerutil.SetControllerReference(overcommitClass, webhookConfig, r.Scheme)
-	})
-	if err != nil {
-		logger.Error(err, "Failed to create or update MutatingWebhookConfiguration")
-		return ctrl.Result{}, err
+	// Once the class is served by the shared deployment, its own deployment is not needed
+	if consolidated {
+		if err := deleteClassWebhook(ctx, r.Client, r.Config, overcommitClass.Name); err != nil {
+			logger.Error(err, "Failed to delete the webhook resources of the class")
+			return ctrl.Result{}, err
+		}
 	}
 
 	if getTotalClasses(ctx, r.Client) != nil {
@@ -190,12 +227,12 @@ func (r *OvercommitClassReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// Update the status of the resources
-	if err := r.updateResourcesStatus(ctx, overcommitClass); err != nil {
+	if err := r.updateResourcesStatus(ctx, overcommitClass, deployment, service, provider.Check(ctx, certificate), webhookConfig.Name); err != nil {
 		logger.Error(err, "Error updating resource status")
 		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{
-		RequeueAfter: 10 * time.Second,
+		RequeueAfter: utils.RequeueAfter(&overcommitResource, r.ResyncPeriod),
 	}, nil
 }
@@ -130,3 +130,37 @@ var _ = Describe("OvercommitClass Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("OvercommitClass Controller", func() {
+	Context("When a resource of an OvercommitClass is deleted", func() {
+		It("Should recreate it without waiting for a resync", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-watch",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+				},
+			}
+			Expect(k8sClient.Create(ctx, overcommitClass)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, overcommitClass)).To(Succeed())
+			})
+
+			key := client.ObjectKey{Name: "test-watch-overcommit-webhook", Namespace: os.Getenv("POD_NAMESPACE")}
+			deployment := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, key, deployment)
+			}).Should(Succeed())
+			uid := deployment.UID
+
+			Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+
+			Eventually(func() bool {
+				recreated := &appsv1.Deployment{}
+				return k8sClient.Get(ctx, key, recreated) == nil && recreated.UID != uid
+			}).Should(BeTrue())
+		})
+	})
+})
@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -106,12 +107,13 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 
 	err = (&OvercommitClassReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		Recorder: k8sManager.GetEventRecorderFor("overcommitclass-controller"),
+		Config:   config.Config{Namespace: "k8s-overcommit", Image: "registry/k8s-overcommit:test", ServiceAccountName: "k8s-overcommit"},
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
-	os.Setenv("POD_NAMESPACE", "k8s-overcommit")
 	os.Setenv("LABEL_OVERCOMMIT_CLASS", "test")
 
 	go func() {
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package events records the lifecycle transitions of the resources generated by the operator
+// as Kubernetes Events on the Overcommit and OvercommitClass resources that own them.
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Reasons of the events.
+const (
+	ReasonCreated            = "Created"
+	ReasonImageUpgraded      = "ImageUpgraded"
+	ReasonCertificateRotated = "CertificateRotated"
+	ReasonWebhookUnreachable = "WebhookUnreachable"
+	ReasonWebhookReachable   = "WebhookReachable"
+	ReasonDriftCorrected     = "DriftCorrected"
+
+	ReasonLabelMigrationStarted   = "LabelMigrationStarted"
+	ReasonLabelMigrationCompleted = "LabelMigrationCompleted"
+	ReasonNamespacesRelabeled     = "NamespacesRelabeled"
+)
+
+// Created records that the object was created for the owner.
+func Created(recorder record.EventRecorder, owner runtime.Object, object client.Object, result controllerutil.OperationResult) {
+	if result != controllerutil.OperationResultCreated {
+		return
+	}
+	recorder.Eventf(owner, corev1.EventTypeNormal, ReasonCreated, "Created %s %s", kind(object), object.GetName())
+}
+
+// DriftCorrected records that the fields of a generated object changed by the manager were set
+// back to their desired state.
+func DriftCorrected(recorder record.EventRecorder, owner runtime.Object, kind, name, manager string) {
+	recorder.Eventf(owner, corev1.EventTypeWarning, ReasonDriftCorrected, "Corrected the changes of %s to %s %s", manager, kind, name)
+}
+
+// CertificateReissued records that the builtin certificate provider issued a new certificate in
+// the secret for the reason.
+func CertificateReissued(recorder record.EventRecorder, owner runtime.Object, secretName, reason string) {
+	recorder.Eventf(owner, corev1.EventTypeNormal, ReasonCertificateRotated, "Issued a new certificate in secret %s: %s", secretName, reason)
+}
+
+// LabelMigration records the start and the completion of a label migration.
+func LabelMigration(recorder record.EventRecorder, owner runtime.Object, before, after *overcommit.LabelMigrationStatus) {
+	if after == nil {
+		return
+	}
+	started := before == nil || before.From != after.From || before.To != after.To || !before.StartTime.Equal(&after.StartTime)
+	switch {
+	case started && after.Phase == overcommit.LabelMigrationInProgress:
+		recorder.Eventf(owner, corev1.EventTypeNormal, ReasonLabelMigrationStarted,
+			"Migrating from label %s to %s, both are honored until %s", after.From, after.To, after.Deadline.UTC().Format(time.RFC3339))
+	case (started || before.Phase != after.Phase) && after.Phase == overcommit.LabelMigrationCompleted:
+		recorder.Eventf(owner, corev1.EventTypeNormal, ReasonLabelMigrationCompleted,
+			"Migrated from label %s to %s, %d pods and %d namespaces were still labeled only with %s", after.From, after.To, after.PendingPods, after.PendingNamespaces, after.From)
+	}
+}
+
+// NamespacesRelabeled records that count namespaces were relabeled from the label from to to.
+func NamespacesRelabeled(recorder record.EventRecorder, owner runtime.Object, count int, from, to string) {
+	recorder.Eventf(owner, corev1.EventTypeNormal, ReasonNamespacesRelabeled, "Relabeled %d namespaces from %s to %s", count, from, to)
+}
+
+// WebhookReachability records the transitions of the WebhookReachable condition.
+func WebhookReachability(recorder record.EventRecorder, owner runtime.Object, before, after *metav1.Condition) {
+	if after == nil || (before != nil && before.Status == after.Status) {
+		return
+	}
+	switch after.Status {
+	case metav1.ConditionFalse:
+		recorder.Event(owner, corev1.EventTypeWarning, ReasonWebhookUnreachable, after.Message)
+	case metav1.ConditionTrue:
+		// A new resource is reachable from the start, there is nothing to recover from
+		if before != nil {
+			recorder.Event(owner, corev1.EventTypeNormal, ReasonWebhookReachable, after.Message)
+		}
+	}
+}
+
+// ImageUpgrades records an event on the owner of the kind ownerKind when the image of one of
+// its Deployments changes. It doesn't filter any event.
+func ImageUpgrades(recorder record.EventRecorder, ownerKind string) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, ok := e.ObjectOld.(*appsv1.Deployment)
+			deployment, newOk := e.ObjectNew.(*appsv1.Deployment)
+			if !ok || !newOk {
+				return true
+			}
+			if before, after := images(old), images(deployment); before != after {
+				if owner := ownerOf(deployment, ownerKind); owner != nil {
+					recorder.Eventf(owner, corev1.EventTypeNormal, ReasonImageUpgraded,
+						"Deployment %s upgraded from %s to %s", deployment.Name, before, after)
+				}
+			}
+			return true
+		},
+	}
+}
+
+// CertificateRotations records an event on the owner of the kind ownerKind when cert-manager
+// issues a new revision of one of its Certificates. It doesn't filter any event.
+func CertificateRotations(recorder record.EventRecorder, ownerKind string) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, ok := e.ObjectOld.(*certmanagerv1.Certificate)
+			certificate, newOk := e.ObjectNew.(*certmanagerv1.Certificate)
+			if !ok || !newOk || old.Status.Revision == nil || certificate.Status.Revision == nil {
+				return true
+			}
+			if *certificate.Status.Revision > *old.Status.Revision {
+				if owner := ownerOf(certificate, ownerKind); owner != nil {
+					recorder.Eventf(owner, corev1.EventTypeNormal, ReasonCertificateRotated,
+						"Certificate %s rotated to revision %d", certificate.Name, *certificate.Status.Revision)
+				}
+			}
+			return true
+		},
+	}
+}
+
+// ownerOf returns a reference to the controller of the object when it is of the kind ownerKind.
+func ownerOf(object client.Object, ownerKind string) runtime.Object {
+	owner := metav1.GetControllerOf(object)
+	if owner == nil || owner.Kind != ownerKind || !strings.HasPrefix(owner.APIVersion, overcommit.GroupVersion.Group+"/") {
+		return nil
+	}
+	return &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: owner.APIVersion, Kind: owner.Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: owner.Name, UID: owner.UID},
+	}
+}
+
+func images(deployment *appsv1.Deployment) string {
+	images := make([]string, 0, len(deployment.Spec.Template.Spec.Containers))
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return strings.Join(images, ",")
+}
+
+func kind(object client.Object) string {
+	if kind := object.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	typeName := fmt.Sprintf("%T", object)
+	return typeName[strings.LastIndex(typeName, ".")+1:]
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func ownedBy(kind string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name: "high-overcommit-webhook",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: overcommit.GroupVersion.String(),
+			Kind:       kind,
+			Name:       "high",
+			Controller: ptr.To(true),
+		}},
+	}
+}
+
+func deployment(image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: ownedBy("OvercommitClass"),
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "webhook", Image: image}},
+		}}},
+	}
+}
+
+func TestImageUpgrades(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	predicate := ImageUpgrades(recorder, "OvercommitClass")
+
+	if !predicate.Update(event.UpdateEvent{ObjectOld: deployment("operator:1.0.0"), ObjectNew: deployment("operator:1.0.0")}) {
+		t.Error("Expected the update not to be filtered")
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event without an image change, got %s", <-recorder.Events)
+	}
+
+	predicate.Update(event.UpdateEvent{ObjectOld: deployment("operator:1.0.0"), ObjectNew: deployment("operator:1.1.0")})
+	if got := <-recorder.Events; got != "Normal ImageUpgraded Deployment high-overcommit-webhook upgraded from operator:1.0.0 to operator:1.1.0" {
+		t.Errorf("Unexpected event %q", got)
+	}
+
+	// Deployments of other owners are ignored
+	ImageUpgrades(recorder, "Overcommit").Update(event.UpdateEvent{ObjectOld: deployment("operator:1.0.0"), ObjectNew: deployment("operator:1.1.0")})
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event for a deployment of another owner, got %s", <-recorder.Events)
+	}
+}
+
+func TestCertificateRotations(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	certificate := func(revision int) *certmanagerv1.Certificate {
+		return &certmanagerv1.Certificate{
+			ObjectMeta: ownedBy("OvercommitClass"),
+			Status:     certmanagerv1.CertificateStatus{Revision: ptr.To(revision)},
+		}
+	}
+
+	CertificateRotations(recorder, "OvercommitClass").Update(event.UpdateEvent{ObjectOld: certificate(1), ObjectNew: certificate(2)})
+	if got := <-recorder.Events; got != "Normal CertificateRotated Certificate high-overcommit-webhook rotated to revision 2" {
+		t.Errorf("Unexpected event %q", got)
+	}
+}
+
+func TestCreated(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	owner := &overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}}
+
+	Created(recorder, owner, deployment("operator:1.0.0"), controllerutil.OperationResultUpdated)
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event for an update, got %s", <-recorder.Events)
+	}
+	Created(recorder, owner, deployment("operator:1.0.0"), controllerutil.OperationResultCreated)
+	if got := <-recorder.Events; got != "Normal Created Created Deployment high-overcommit-webhook" {
+		t.Errorf("Unexpected event %q", got)
+	}
+}
+
+func TestCertificateReissued(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	owner := &overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}}
+
+	CertificateReissued(recorder, owner, "high-overcommit-webhook-secret", "the certificate expires soon")
+	if got := <-recorder.Events; got != "Normal CertificateRotated Issued a new certificate in secret high-overcommit-webhook-secret: the certificate expires soon" {
+		t.Errorf("Unexpected event %q", got)
+	}
+}
+
+func TestWebhookReachability(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	owner := &overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}}
+	reachable := &metav1.Condition{Status: metav1.ConditionTrue, Message: "reachable"}
+	unreachable := &metav1.Condition{Status: metav1.ConditionFalse, Message: "no ready endpoints"}
+
+	WebhookReachability(recorder, owner, nil, reachable)
+	WebhookReachability(recorder, owner, reachable, reachable)
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event without a transition, got %s", <-recorder.Events)
+	}
+
+	WebhookReachability(recorder, owner, reachable, unreachable)
+	if got := <-recorder.Events; got != "Warning WebhookUnreachable no ready endpoints" {
+		t.Errorf("Unexpected event %q", got)
+	}
+	WebhookReachability(recorder, owner, unreachable, reachable)
+	if got := <-recorder.Events; got != "Normal WebhookReachable reachable" {
+		t.Errorf("Unexpected event %q", got)
+	}
+}
+
+func TestLabelMigration(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	owner := &overcommit.Overcommit{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	started := &overcommit.LabelMigrationStatus{
+		From:      "inditex.com/overcommit-class",
+		To:        "inditex.dev/overcommit-class",
+		Phase:     overcommit.LabelMigrationInProgress,
+		StartTime: metav1.NewTime(time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)),
+		Deadline:  metav1.NewTime(time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)),
+	}
+
+	LabelMigration(recorder, owner, nil, started)
+	if got := <-recorder.Events; got != "Normal LabelMigrationStarted Migrating from label inditex.com/overcommit-class to inditex.dev/overcommit-class, both are honored until 2025-06-02T10:00:00Z" {
+		t.Errorf("Unexpected event %q", got)
+	}
+
+	progressed := started.DeepCopy()
+	progressed.PendingPods = 3
+	LabelMigration(recorder, owner, started, progressed)
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event for the progress of the migration, got %s", <-recorder.Events)
+	}
+
+	completed := progressed.DeepCopy()
+	completed.Phase = overcommit.LabelMigrationCompleted
+	LabelMigration(recorder, owner, progressed, completed)
+	if got := <-recorder.Events; got != "Normal LabelMigrationCompleted Migrated from label inditex.com/overcommit-class to inditex.dev/overcommit-class, 3 pods and 0 namespaces were still labeled only with inditex.com/overcommit-class" {
+		t.Errorf("Unexpected event %q", got)
+	}
+}
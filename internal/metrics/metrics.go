@@ -59,6 +59,34 @@ var (
 		},
 		[]string{"class", "kind", "name", "namespace"},
 	)
+	K8sOvercommitOperatorAuditedPodsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "k8s_overcommit_operator_audited_pods_total",
+			Help: "Total number of pods audited by an overcommit class in Audit mode",
+		},
+		[]string{"class"},
+	)
+	K8sOvercommitOperatorAuditRequestSavingsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "k8s_overcommit_operator_audit_request_savings_total",
+			Help: "Requests an overcommit class in Audit mode would have saved, in cores for CPU and bytes for memory and storage",
+		},
+		[]string{"class", "resource"},
+	)
+	K8sOvercommitOperatorDriftCorrectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "k8s_overcommit_operator_drift_corrections_total",
+			Help: "Total number of generated resources set back to their desired state after another field manager changed them",
+		},
+		[]string{"kind", "manager"},
+	)
+	K8sOvercommitOperatorResolutionFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "k8s_overcommit_operator_resolution_failures_total",
+			Help: "Total number of pods whose overcommit couldn't be decided, per reason and onError policy of the class of the webhook",
+		},
+		[]string{"class", "reason", "policy"},
+	)
 )
 
 func init() {
@@ -69,4 +97,8 @@ func init() {
 	metrics.Registry.MustRegister(K8sOvercommitOperatorVersion)
 	metrics.Registry.MustRegister(K8sOvercommitOperatorClass)
 	metrics.Registry.MustRegister(K8sOvercommitPodMutated)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorAuditedPodsTotal)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorAuditRequestSavingsTotal)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorDriftCorrectionsTotal)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorResolutionFailuresTotal)
 }
@@ -42,6 +42,18 @@ func (suite *MetricsTestSuite) TestK8sOvercommitOperatorPodsNotMutatedTotal() {
 	assert.Equal(suite.T(), 1.0, count)
 }
 
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorAuditRequestSavingsTotal() {
+	K8sOvercommitOperatorAuditRequestSavingsTotal.WithLabelValues("test", "cpu").Add(0.5)
+	count := testutil.ToFloat64(K8sOvercommitOperatorAuditRequestSavingsTotal.WithLabelValues("test", "cpu"))
+	assert.Equal(suite.T(), 0.5, count)
+}
+
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorDriftCorrectionsTotal() {
+	K8sOvercommitOperatorDriftCorrectionsTotal.WithLabelValues("Deployment", "kubectl-edit").Inc()
+	count := testutil.ToFloat64(K8sOvercommitOperatorDriftCorrectionsTotal.WithLabelValues("Deployment", "kubectl-edit"))
+	assert.Equal(suite.T(), 1.0, count)
+}
+
 func TestMetricsTestSuite(t *testing.T) {
 	suite.Run(t, new(MetricsTestSuite))
 }
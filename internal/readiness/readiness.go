@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package readiness tells whether the resources generated by the operator work, not only
+// whether they exist.
+package readiness
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the readiness of a resource.
+const (
+	ReasonNotFound          = "NotFound"
+	ReasonGetFailed         = "GetFailed"
+	ReasonAvailable         = "Available"
+	ReasonRolloutInProgress = "RolloutInProgress"
+	ReasonUnavailable       = "Unavailable"
+	ReasonIssued            = "Issued"
+	ReasonNotIssued         = "NotIssued"
+	ReasonExpired           = "Expired"
+	ReasonIssuerReady       = "IssuerReady"
+	ReasonIssuerNotReady    = "IssuerNotReady"
+	ReasonCreated           = "Created"
+	ReasonCABundleMissing   = "CABundleMissing"
+	ReasonNoReadyEndpoints  = "NoReadyEndpoints"
+	ReasonWebhookReady      = "WebhookReady"
+)
+
+// Result is the readiness of a resource.
+type Result struct {
+	Ready   bool
+	Reason  string
+	Message string
+}
+
+// Check gets the object and tells whether it works with ready.
+func Check[T client.Object](ctx context.Context, c client.Reader, kind string, key client.ObjectKey, object T, ready func(T) Result) Result {
+	if err := c.Get(ctx, key, object); err != nil {
+		return GetError(kind, err)
+	}
+	return ready(object)
+}
+
+// GetError is the readiness of a resource that couldn't be read.
+func GetError(kind string, err error) Result {
+	if apierrors.IsNotFound(err) {
+		return Result{Reason: ReasonNotFound, Message: fmt.Sprintf("%s not found", kind)}
+	}
+	return Result{Reason: ReasonGetFailed, Message: fmt.Sprintf("error getting the %s: %v", kind, err)}
+}
+
+// Deployment is ready when its current generation is rolled out and every replica is available.
+func Deployment(deployment *appsv1.Deployment) Result {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	if status.ObservedGeneration < deployment.Generation {
+		return Result{Reason: ReasonRolloutInProgress, Message: "the deployment controller hasn't observed the last generation yet"}
+	}
+	if status.UpdatedReplicas < replicas {
+		return Result{Reason: ReasonRolloutInProgress, Message: fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas)}
+	}
+	if status.Replicas > status.UpdatedReplicas {
+		return Result{Reason: ReasonRolloutInProgress, Message: fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas)}
+	}
+	if status.AvailableReplicas < replicas {
+		return Result{Reason: ReasonUnavailable, Message: fmt.Sprintf("%d of %d replicas available", status.AvailableReplicas, replicas)}
+	}
+	return Result{Ready: true, Reason: ReasonAvailable, Message: fmt.Sprintf("%d of %d replicas available", status.AvailableReplicas, replicas)}
+}
+
+// Certificate is ready when cert-manager reports it as Ready for its current generation.
+func Certificate(certificate *certmanagerv1.Certificate) Result {
+	for _, condition := range certificate.Status.Conditions {
+		if condition.Type != certmanagerv1.CertificateConditionReady {
+			continue
+		}
+		if condition.Status == cmmeta.ConditionTrue && condition.ObservedGeneration >= certificate.Generation {
+			return Result{Ready: true, Reason: ReasonIssued, Message: condition.Message}
+		}
+		return Result{Reason: ReasonNotIssued, Message: conditionMessage(condition.Reason, condition.Message)}
+	}
+	return Result{Reason: ReasonNotIssued, Message: "the certificate has no Ready condition yet"}
+}
+
+// Secret is ready when it holds a certificate and its key, and the certificate isn't expired.
+func Secret(secret *corev1.Secret) Result {
+	pair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return Result{Reason: ReasonNotIssued, Message: fmt.Sprintf("the secret doesn't hold a certificate and its key: %v", err)}
+	}
+	certificate, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return Result{Reason: ReasonNotIssued, Message: fmt.Sprintf("the certificate of the secret can't be parsed: %v", err)}
+	}
+	if time.Now().After(certificate.NotAfter) {
+		return Result{Reason: ReasonExpired, Message: fmt.Sprintf("the certificate expired at %s", certificate.NotAfter.UTC().Format(time.RFC3339))}
+	}
+	return Result{Ready: true, Reason: ReasonIssued, Message: fmt.Sprintf("the certificate is valid until %s", certificate.NotAfter.UTC().Format(time.RFC3339))}
+}
+
+// Issuer is ready when cert-manager reports it as Ready.
+func Issuer(issuer *certmanagerv1.Issuer) Result {
+	for _, condition := range issuer.Status.Conditions {
+		if condition.Type != certmanagerv1.IssuerConditionReady {
+			continue
+		}
+		if condition.Status == cmmeta.ConditionTrue {
+			return Result{Ready: true, Reason: ReasonIssuerReady, Message: condition.Message}
+		}
+		return Result{Reason: ReasonIssuerNotReady, Message: conditionMessage(condition.Reason, condition.Message)}
+	}
+	return Result{Reason: ReasonIssuerNotReady, Message: "the issuer has no Ready condition yet"}
+}
+
+// Service is ready once it exists: the endpoints behind it are checked by the webhook
+// configurations that call it.
+func Service(service *corev1.Service) Result {
+	return Result{Ready: true, Reason: ReasonCreated, Message: fmt.Sprintf("service %s/%s created", service.Namespace, service.Name)}
+}
+
+// CABundles tells whether the CA bundle is injected in every client config.
+func CABundles(clientConfigs ...admissionv1.WebhookClientConfig) Result {
+	for _, clientConfig := range clientConfigs {
+		if len(clientConfig.CABundle) == 0 {
+			return Result{Reason: ReasonCABundleMissing, Message: "the CA bundle of the certificate is not injected yet"}
+		}
+	}
+	return Result{Ready: true, Reason: ReasonWebhookReady, Message: "the CA bundle is injected"}
+}
+
+// MutatingWebhookConfiguration is ready when the CA bundle is injected in every webhook and the
+// services they call have ready endpoints.
+func MutatingWebhookConfiguration(ctx context.Context, c client.Reader, configuration *admissionv1.MutatingWebhookConfiguration) Result {
+	clientConfigs := make([]admissionv1.WebhookClientConfig, 0, len(configuration.Webhooks))
+	for _, webhook := range configuration.Webhooks {
+		clientConfigs = append(clientConfigs, webhook.ClientConfig)
+	}
+	return webhookConfiguration(ctx, c, clientConfigs)
+}
+
+// ValidatingWebhookConfiguration is ready when the CA bundle is injected in every webhook and
+// the services they call have ready endpoints.
+func ValidatingWebhookConfiguration(ctx context.Context, c client.Reader, configuration *admissionv1.ValidatingWebhookConfiguration) Result {
+	clientConfigs := make([]admissionv1.WebhookClientConfig, 0, len(configuration.Webhooks))
+	for _, webhook := range configuration.Webhooks {
+		clientConfigs = append(clientConfigs, webhook.ClientConfig)
+	}
+	return webhookConfiguration(ctx, c, clientConfigs)
+}
+
+func webhookConfiguration(ctx context.Context, c client.Reader, clientConfigs []admissionv1.WebhookClientConfig) Result {
+	if result := CABundles(clientConfigs...); !result.Ready {
+		return result
+	}
+	for _, clientConfig := range clientConfigs {
+		if clientConfig.Service == nil {
+			continue
+		}
+		if result := ServiceEndpoints(ctx, c, clientConfig.Service.Namespace, clientConfig.Service.Name); !result.Ready {
+			return result
+		}
+	}
+	return Result{Ready: true, Reason: ReasonWebhookReady, Message: "the CA bundle is injected and the service has ready endpoints"}
+}
+
+// ServiceEndpoints tells whether the service has at least one ready endpoint. The EndpointSlices
+// are not watched, c should read them from the API server: a cached client would start an
+// informer on every EndpointSlice of the cluster and return stale endpoints.
+func ServiceEndpoints(ctx context.Context, c client.Reader, namespace, name string) Result {
+	slices := &discoveryv1.EndpointSliceList{}
+	if err := c.List(ctx, slices, client.InNamespace(namespace), client.MatchingLabels{discoveryv1.LabelServiceName: name}); err != nil {
+		return Result{Reason: ReasonGetFailed, Message: fmt.Sprintf("error listing the endpoints of service %s/%s: %v", namespace, name, err)}
+	}
+	if ready := readyEndpoints(slices.Items); ready > 0 {
+		return Result{Ready: true, Reason: ReasonWebhookReady, Message: fmt.Sprintf("service %s/%s has %d ready endpoints", namespace, name, ready)}
+	}
+	return Result{Reason: ReasonNoReadyEndpoints, Message: fmt.Sprintf("service %s/%s has no ready endpoints", namespace, name)}
+}
+
+// readyEndpoints counts the ready endpoints of the slices. An endpoint without the ready
+// condition is considered ready, as the API documents.
+func readyEndpoints(slices []discoveryv1.EndpointSlice) int {
+	ready := 0
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+	return ready
+}
+
+func conditionMessage(reason, message string) string {
+	if message == "" {
+		return reason
+	}
+	if reason == "" {
+		return message
+	}
+	return reason + ": " + message
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package readiness
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDeployment(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation int64
+		status     appsv1.DeploymentStatus
+		ready      bool
+		reason     string
+	}{
+		{
+			name:       "available",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			ready:      true,
+			reason:     ReasonAvailable,
+		},
+		{
+			name:       "generation not observed",
+			generation: 3,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			reason:     ReasonRolloutInProgress,
+		},
+		{
+			name:       "old replicas left",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 3},
+			reason:     ReasonRolloutInProgress,
+		},
+		{
+			name:       "crashlooping",
+			generation: 2,
+			status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 0},
+			reason:     ReasonUnavailable,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: test.generation},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+				Status:     test.status,
+			}
+			result := Deployment(deployment)
+			if result.Ready != test.ready || result.Reason != test.reason {
+				t.Errorf("Expected ready %v with reason %s, got %+v", test.ready, test.reason, result)
+			}
+		})
+	}
+}
+
+func TestCertificate(t *testing.T) {
+	certificate := &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+	if result := Certificate(certificate); result.Ready || result.Reason != ReasonNotIssued {
+		t.Errorf("Expected a certificate without conditions not to be ready, got %+v", result)
+	}
+
+	certificate.Status.Conditions = []certmanagerv1.CertificateCondition{{
+		Type:               certmanagerv1.CertificateConditionReady,
+		Status:             cmmeta.ConditionFalse,
+		Reason:             "DoesNotExist",
+		Message:            "Issuing certificate as Secret does not exist",
+		ObservedGeneration: 1,
+	}}
+	result := Certificate(certificate)
+	if result.Ready || result.Message != "DoesNotExist: Issuing certificate as Secret does not exist" {
+		t.Errorf("Expected an unissued certificate not to be ready, got %+v", result)
+	}
+
+	certificate.Status.Conditions[0].Status = cmmeta.ConditionTrue
+	if result := Certificate(certificate); !result.Ready || result.Reason != ReasonIssued {
+		t.Errorf("Expected an issued certificate to be ready, got %+v", result)
+	}
+}
+
+// selfSigned returns a PEM encoded self-signed certificate valid until notAfter and its key.
+func selfSigned(t *testing.T, notAfter time.Time) (cert, key []byte) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "webhook"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestSecret(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	if result := Secret(secret); result.Ready || result.Reason != ReasonNotIssued {
+		t.Errorf("Expected an empty secret not to be ready, got %+v", result)
+	}
+
+	secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey] = selfSigned(t, time.Now().Add(-time.Minute))
+	if result := Secret(secret); result.Ready || result.Reason != ReasonExpired {
+		t.Errorf("Expected an expired certificate not to be ready, got %+v", result)
+	}
+
+	secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey] = selfSigned(t, time.Now().Add(time.Hour))
+	if result := Secret(secret); !result.Ready || result.Reason != ReasonIssued {
+		t.Errorf("Expected a valid certificate to be ready, got %+v", result)
+	}
+}
+
+func TestMutatingWebhookConfiguration(t *testing.T) {
+	configuration := &admissionv1.MutatingWebhookConfiguration{
+		Webhooks: []admissionv1.MutatingWebhook{{
+			Name: "pod.overcommit.inditex.dev",
+			ClientConfig: admissionv1.WebhookClientConfig{
+				Service: &admissionv1.ServiceReference{Namespace: "k8s-overcommit", Name: "test-webhook-service"},
+			},
+		}},
+	}
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-webhook-service-abcde",
+			Namespace: "k8s-overcommit",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "test-webhook-service"},
+		},
+		Endpoints: []discoveryv1.Endpoint{{
+			Addresses:  []string{"10.0.0.1"},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+		}},
+	}
+	c := fake.NewClientBuilder().WithObjects(slice).Build()
+
+	if result := MutatingWebhookConfiguration(context.Background(), c, configuration); result.Ready || result.Reason != ReasonCABundleMissing {
+		t.Errorf("Expected a webhook without CA bundle not to be ready, got %+v", result)
+	}
+
+	configuration.Webhooks[0].ClientConfig.CABundle = []byte("ca")
+	if result := MutatingWebhookConfiguration(context.Background(), c, configuration); result.Ready || result.Reason != ReasonNoReadyEndpoints {
+		t.Errorf("Expected a webhook without ready endpoints not to be ready, got %+v", result)
+	}
+
+	slice.Endpoints[0].Conditions.Ready = ptr.To(true)
+	c = fake.NewClientBuilder().WithObjects(slice).Build()
+	if result := MutatingWebhookConfiguration(context.Background(), c, configuration); !result.Ready {
+		t.Errorf("Expected a webhook with CA bundle and ready endpoints to be ready, got %+v", result)
+	}
+}
+
+func TestGetError(t *testing.T) {
+	result := GetError("Deployment", apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "test"))
+	if result.Ready || result.Reason != ReasonNotFound {
+		t.Errorf("Expected a missing resource not to be ready, got %+v", result)
+	}
+}
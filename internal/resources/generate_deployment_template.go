@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// MergeDeploymentTemplates returns the base template with the fields set in the override
+// replacing its own. Either of them may be nil.
+func MergeDeploymentTemplates(base, override *overcommit.DeploymentTemplate) *overcommit.DeploymentTemplate {
+	if override == nil {
+		return base.DeepCopy()
+	}
+	merged := base.DeepCopy()
+	if merged == nil {
+		return override.DeepCopy()
+	}
+	override = override.DeepCopy()
+	if override.Replicas != nil {
+		merged.Replicas = override.Replicas
+	}
+	if override.Resources != nil {
+		merged.Resources = override.Resources
+	}
+	if override.NodeSelector != nil {
+		merged.NodeSelector = override.NodeSelector
+	}
+	if override.Tolerations != nil {
+		merged.Tolerations = override.Tolerations
+	}
+	if override.Affinity != nil {
+		merged.Affinity = override.Affinity
+	}
+	if override.TopologySpreadConstraints != nil {
+		merged.TopologySpreadConstraints = override.TopologySpreadConstraints
+	}
+	if override.PriorityClassName != "" {
+		merged.PriorityClassName = override.PriorityClassName
+	}
+	if override.SecurityContext != nil {
+		merged.SecurityContext = override.SecurityContext
+	}
+	if override.ContainerSecurityContext != nil {
+		merged.ContainerSecurityContext = override.ContainerSecurityContext
+	}
+	if override.PodDisruptionBudget != nil {
+		merged.PodDisruptionBudget = override.PodDisruptionBudget
+	}
+	return merged
+}
+
+// applyDeploymentTemplate sets the fields of the template on the deployment. Without an affinity
+// in the template, the pods of the deployment prefer to run on different nodes.
+func applyDeploymentTemplate(deployment *appsv1.Deployment, template *overcommit.DeploymentTemplate) {
+	podSpec := &deployment.Spec.Template.Spec
+	if template == nil || template.Affinity == nil {
+		podSpec.Affinity = preferDifferentNodes(deployment.Spec.Selector)
+	}
+	if template == nil {
+		return
+	}
+	template = template.DeepCopy()
+
+	if template.Replicas != nil {
+		deployment.Spec.Replicas = template.Replicas
+	}
+	if template.Affinity != nil {
+		podSpec.Affinity = template.Affinity
+	}
+	podSpec.NodeSelector = template.NodeSelector
+	podSpec.Tolerations = template.Tolerations
+	podSpec.TopologySpreadConstraints = template.TopologySpreadConstraints
+	podSpec.PriorityClassName = template.PriorityClassName
+	podSpec.SecurityContext = template.SecurityContext
+	for i := range podSpec.Containers {
+		if template.Resources != nil {
+			podSpec.Containers[i].Resources = *template.Resources
+		}
+		podSpec.Containers[i].SecurityContext = template.ContainerSecurityContext
+	}
+}
+
+// preferDifferentNodes returns an affinity that spreads the pods of the selector over the nodes.
+func preferDifferentNodes(selector *metav1.LabelSelector) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: selector.DeepCopy(),
+					TopologyKey:   corev1.LabelHostname,
+				},
+			}},
+		},
+	}
+}
+
+// GeneratePodDisruptionBudget returns the PodDisruptionBudget of a webhook deployment. It allows
+// one unavailable pod unless the template sets minAvailable or maxUnavailable.
+func GeneratePodDisruptionBudget(deployment appsv1.Deployment, template *overcommit.DeploymentTemplate) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt32(1)
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector:       deployment.Spec.Selector.DeepCopy(),
+		MaxUnavailable: &maxUnavailable,
+	}
+	if template != nil && template.PodDisruptionBudget != nil {
+		budget := template.PodDisruptionBudget.DeepCopy()
+		if budget.MinAvailable != nil {
+			spec.MinAvailable = budget.MinAvailable
+			spec.MaxUnavailable = nil
+		}
+		if budget.MaxUnavailable != nil {
+			spec.MaxUnavailable = budget.MaxUnavailable
+		}
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Labels: map[string]string{
+				"app": deployment.Name,
+			},
+		},
+		Spec: spec,
+	}
+}
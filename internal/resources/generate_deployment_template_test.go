@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"slices"
+	"testing"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestCreateDeploymentTemplate(t *testing.T) {
+	template := &overcommit.DeploymentTemplate{
+		Replicas:          ptr.To(int32(3)),
+		NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:       []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists}},
+		PriorityClassName: "system-cluster-critical",
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}
+	class := overcommit.OvercommitClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-class"},
+		Spec: overcommit.OvercommitClassSpec{
+			Deployment: &overcommit.DeploymentTemplate{Replicas: ptr.To(int32(2)), PriorityClassName: "high"},
+		},
+	}
+
+	deployment := CreateDeployment(class, template, testConfig)
+	podSpec := deployment.Spec.Template.Spec
+
+	if *deployment.Spec.Replicas != 2 {
+		t.Errorf("Expected the replicas of the class, got %d", *deployment.Spec.Replicas)
+	}
+	if podSpec.PriorityClassName != "high" {
+		t.Errorf("Expected the priority class of the class, got '%s'", podSpec.PriorityClassName)
+	}
+	if _, ok := podSpec.NodeSelector["node-role.kubernetes.io/infra"]; !ok || len(podSpec.Tolerations) != 1 {
+		t.Errorf("Expected the node selector and tolerations of the Overcommit template, got %v and %v", podSpec.NodeSelector, podSpec.Tolerations)
+	}
+	if cpu := podSpec.Containers[0].Resources.Requests[corev1.ResourceCPU]; cpu.String() != "100m" || podSpec.Containers[0].Resources.Limits != nil {
+		t.Errorf("Expected the resources of the template, got %v", podSpec.Containers[0].Resources)
+	}
+	if template.Replicas == nil || *template.Replicas != 3 {
+		t.Error("Expected the Overcommit template not to be modified")
+	}
+}
+
+func TestDefaultAffinity(t *testing.T) {
+	deployment := CreateDeployment(overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: "test-class"}}, nil, testConfig)
+
+	affinity := deployment.Spec.Template.Spec.Affinity
+	if affinity == nil || affinity.PodAntiAffinity == nil || len(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Fatalf("Expected the pods to prefer different nodes, got %v", affinity)
+	}
+	term := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+	if term.TopologyKey != corev1.LabelHostname || term.LabelSelector.MatchLabels["app"] != "test-class-overcommit-webhook" {
+		t.Errorf("Unexpected anti-affinity term %v", term)
+	}
+
+	custom := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+	deployment = CreateDeployment(overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: "test-class"}}, &overcommit.DeploymentTemplate{Affinity: custom}, testConfig)
+	if deployment.Spec.Template.Spec.Affinity.PodAntiAffinity != nil {
+		t.Error("Expected the affinity of the template to replace the default one")
+	}
+}
+
+func TestGeneratePodMutatingDeploymentReplicas(t *testing.T) {
+	overcommitObject := overcommit.Overcommit{Spec: overcommit.OvercommitSpec{
+		Deployment: &overcommit.DeploymentTemplate{Replicas: ptr.To(int32(4))},
+	}}
+	if replicas := *GeneratePodMutatingDeployment(overcommitObject, testConfig).Spec.Replicas; replicas != 4 {
+		t.Errorf("Expected the replicas of the deployment template, got %d", replicas)
+	}
+
+	overcommitObject.Spec.MutatingWebhook = &overcommit.MutatingWebhookSpec{Replicas: ptr.To(int32(3))}
+	if replicas := *GeneratePodMutatingDeployment(overcommitObject, testConfig).Spec.Replicas; replicas != 3 {
+		t.Errorf("Expected the replicas of mutatingWebhook, got %d", replicas)
+	}
+}
+
+func TestGenerateOvercommitClassControllerDeploymentLeaderElection(t *testing.T) {
+	deployment := GenerateOvercommitClassControllerDeployment(overcommit.Overcommit{}, testConfig)
+	if slices.Contains(deployment.Spec.Template.Spec.Containers[0].Args, "--leader-elect") {
+		t.Error("Expected no leader election with a single replica")
+	}
+
+	deployment = GenerateOvercommitClassControllerDeployment(overcommit.Overcommit{Spec: overcommit.OvercommitSpec{
+		Deployment: &overcommit.DeploymentTemplate{Replicas: ptr.To(int32(2))},
+	}}, testConfig)
+	if !slices.Contains(deployment.Spec.Template.Spec.Containers[0].Args, "--leader-elect") {
+		t.Error("Expected leader election with several replicas")
+	}
+}
+
+func TestGenerateValidatingDeploymentsRoles(t *testing.T) {
+	pods := GeneratePodValidatingDeployment(overcommit.Overcommit{}, testConfig)
+	if !slices.Contains(pods.Spec.Template.Spec.Containers[0].Args, "--role=validating-webhook") {
+		t.Errorf("Expected the pod validating webhook role, got %v", pods.Spec.Template.Spec.Containers[0].Args)
+	}
+
+	classes := GenerateOvercommitClassValidatingDeployment(overcommit.Overcommit{}, testConfig)
+	if !slices.Contains(classes.Spec.Template.Spec.Containers[0].Args, "--role=class-webhook") {
+		t.Errorf("Expected the class webhook role, got %v", classes.Spec.Template.Spec.Containers[0].Args)
+	}
+}
+
+func TestGeneratePodDisruptionBudget(t *testing.T) {
+	deployment := GeneratePodValidatingDeployment(overcommit.Overcommit{}, testConfig)
+
+	budget := GeneratePodDisruptionBudget(*deployment, nil)
+	if budget.Name != deployment.Name || budget.Spec.Selector.MatchLabels["app"] != "k8s-overcommit-pod-validating-webhook" {
+		t.Errorf("Expected the budget to select the pods of the deployment, got %s %v", budget.Name, budget.Spec.Selector)
+	}
+	if budget.Spec.MaxUnavailable == nil || budget.Spec.MaxUnavailable.IntValue() != 1 || budget.Spec.MinAvailable != nil {
+		t.Errorf("Expected one unavailable pod by default, got %v", budget.Spec)
+	}
+
+	minAvailable := intstr.FromString("50%")
+	budget = GeneratePodDisruptionBudget(*deployment, &overcommit.DeploymentTemplate{
+		PodDisruptionBudget: &overcommit.PodDisruptionBudgetTemplate{MinAvailable: &minAvailable},
+	})
+	if budget.Spec.MinAvailable == nil || budget.Spec.MinAvailable.String() != "50%" || budget.Spec.MaxUnavailable != nil {
+		t.Errorf("Expected the minAvailable of the template, got %v", budget.Spec)
+	}
+}
@@ -6,17 +6,16 @@
 package resources
 
 import (
-	"os"
-
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func GenerateIssuer() *certmanagerv1.Issuer {
+// GenerateIssuer returns the self-signed Issuer of the certificates in the namespace.
+func GenerateIssuer(namespace string) *certmanagerv1.Issuer {
 	return &certmanagerv1.Issuer{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-issuer",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: namespace,
 		},
 		Spec: certmanagerv1.IssuerSpec{
 			IssuerConfig: certmanagerv1.IssuerConfig{
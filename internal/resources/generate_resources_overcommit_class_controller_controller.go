@@ -6,15 +6,16 @@
 package resources
 
 import (
-	"os"
-
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Overcommit) *appsv1.Deployment {
+// GenerateOvercommitClassControllerDeployment returns the OvercommitClass controller deployment.
+// Its replicas elect a leader when there is more than one.
+func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Overcommit, cfg config.Config) *appsv1.Deployment {
 	replicas := int32(1)
 	labels := overcommitObject.Spec.Labels
 	if labels == nil {
@@ -28,10 +29,10 @@ func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Ove
 		annotations = make(map[string]string)
 	}
 
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-overcommitclass-controller",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.Namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
@@ -46,49 +47,16 @@ func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Ove
 					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:  "overcommit-controller",
-							Image: os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
-							Args: []string{
-								"--metrics-bind-address=:8080",
-								"-metrics-secure=false",
-							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "ENABLE_OVERCOMMIT_CLASS_CONTROLLER",
-									Value: "true",
-								},
-								{
-									Name:  "IMAGE_REGISTRY",
-									Value: os.Getenv("IMAGE_REGISTRY"),
-								},
-								{
-									Name:  "IMAGE_REPOSITORY",
-									Value: os.Getenv("IMAGE_REPOSITORY"),
-								},
-								{
-									Name:  "APP_VERSION",
-									Value: os.Getenv("APP_VERSION"),
-								},
-								{
-									Name:  "POD_NAMESPACE",
-									Value: os.Getenv("POD_NAMESPACE"),
-								},
-								{
-									Name:  "SERVICE_ACCOUNT_NAME",
-									Value: os.Getenv("SERVICE_ACCOUNT_NAME"),
-								},
-								{
-									Name: "POD_NAME",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											FieldPath: "metadata.name",
-										},
-									},
-								},
-							},
+							Image: cfg.Image,
+							Args: roleArgs(cfg, config.RoleClassController,
+								"--service-account-name="+cfg.ServiceAccountName,
+								"--resync-period="+cfg.ResyncPeriod.Duration.String(),
+							),
+							Env: []corev1.EnvVar{podNameEnv()},
 							Ports: []corev1.ContainerPort{
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
 							},
@@ -98,4 +66,10 @@ func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Ove
 			},
 		},
 	}
+	applyDeploymentTemplate(deployment, overcommitObject.Spec.Deployment)
+	if *deployment.Spec.Replicas > 1 {
+		container := &deployment.Spec.Template.Spec.Containers[0]
+		container.Args = append(container.Args, "--leader-elect")
+	}
+	return deployment
 }
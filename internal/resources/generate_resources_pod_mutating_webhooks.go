@@ -6,10 +6,12 @@
 package resources
 
 import (
-	"os"
+	"fmt"
+	"strings"
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	certmanager "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certmanagermeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
@@ -20,9 +22,22 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func CreateDeployment(class overcommit.OvercommitClass) *appsv1.Deployment {
-	replicas := int32(1)
+// PodMutatingWebhookName is the name of the pod mutating webhook deployment shared by every
+// class in the Consolidated topology.
+const PodMutatingWebhookName = "k8s-overcommit-pod-mutating-webhook"
 
+// PodMutatingWebhookPath is the path of the pod mutating webhook. The webhook deployment of
+// a class in the PerClass topology is called on it.
+const PodMutatingWebhookPath = "/mutate--v1-pod"
+
+// ClassWebhookPath returns the path the shared webhook deployment is called on for the class.
+func ClassWebhookPath(name string) string {
+	return PodMutatingWebhookPath + "/" + name
+}
+
+// CreateDeployment returns the webhook deployment of a class in the PerClass topology. The
+// deployment template of the class overrides the template of the Overcommit resource.
+func CreateDeployment(class overcommit.OvercommitClass, template *overcommit.DeploymentTemplate, cfg config.Config) *appsv1.Deployment {
 	if class.Spec.Labels == nil {
 		class.Spec.Labels = make(map[string]string)
 	}
@@ -30,50 +45,72 @@ func CreateDeployment(class overcommit.OvercommitClass) *appsv1.Deployment {
 	labels := class.Spec.Labels
 	labels["app"] = class.ObjectMeta.Name + "-overcommit-webhook"
 
+	deployment := podMutatingDeployment(cfg, class.ObjectMeta.Name+"-overcommit-webhook", class.Name+"-webhook-secret", 1, labels, class.Spec.Annotations, class.Name)
+	applyDeploymentTemplate(deployment, MergeDeploymentTemplates(template, class.Spec.Deployment))
+	return deployment
+}
+
+// GeneratePodMutatingDeployment returns the pod mutating webhook deployment shared by every
+// class in the Consolidated topology. The replicas of mutatingWebhook take precedence over the
+// ones of the deployment template.
+func GeneratePodMutatingDeployment(overcommitObject overcommit.Overcommit, cfg config.Config) *appsv1.Deployment {
+	replicas := int32(2)
+	if overcommitObject.Spec.Deployment != nil && overcommitObject.Spec.Deployment.Replicas != nil {
+		replicas = *overcommitObject.Spec.Deployment.Replicas
+	}
+	if overcommitObject.Spec.MutatingWebhook != nil && overcommitObject.Spec.MutatingWebhook.Replicas != nil {
+		replicas = *overcommitObject.Spec.MutatingWebhook.Replicas
+	}
+
+	labels := make(map[string]string, len(overcommitObject.Spec.Labels)+1)
+	for key, value := range overcommitObject.Spec.Labels {
+		labels[key] = value
+	}
+	labels["app"] = PodMutatingWebhookName
+
+	deployment := podMutatingDeployment(cfg, PodMutatingWebhookName, "pod-mutating-webhook", replicas, labels, overcommitObject.Spec.Annotations, "")
+	applyDeploymentTemplate(deployment, overcommitObject.Spec.Deployment)
+	deployment.Spec.Replicas = &replicas
+	return deployment
+}
+
+// podMutatingDeployment returns a pod mutating webhook deployment. className is only set for
+// the deployment of a class in the PerClass topology.
+func podMutatingDeployment(cfg config.Config, name, secretName string, replicas int32, labels, annotations map[string]string, className string) *appsv1.Deployment {
+	args := roleArgs(cfg, config.RoleMutatingWebhook, "--cert-dir=/etc/webhook/config")
+	if className != "" {
+		args = append(args, "--class-name="+className)
+	}
+
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      class.ObjectMeta.Name + "-overcommit-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Name:      name,
+			Namespace: cfg.Namespace,
 			Labels: map[string]string{
-				"app": class.ObjectMeta.Name + "-overcommit-webhook",
+				"app": name,
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
-					"app": class.ObjectMeta.Name + "-overcommit-webhook",
+					"app": name,
 				},
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
-					Annotations: class.Spec.Annotations,
+					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:    "k8s-overcommit",
-							Image:   os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
+							Image:   cfg.Image,
 							Command: []string{"/manager"},
-							Args: []string{
-								"--metrics-bind-address=:8080",
-								"-metrics-secure=false",
-							},
-							Env: []corev1.EnvVar{
-								{Name: "APP_VERSION", Value: os.Getenv("APP_VERSION")},
-								{Name: "WEBHOOK_CERT_DIR", Value: "/etc/webhook/config"},
-								{Name: "ENABLE_CONTROLLER", Value: "false"},
-								{Name: "ENABLE_POD_MUTATING_WEBHOOK", Value: "true"},
-								{Name: "OVERCOMMIT_CLASS_NAME", Value: class.Name},
-								{Name: "SERVICE_ACCOUNT_NAME", Value: os.Getenv("SERVICE_ACCOUNT_NAME")},
-								{Name: "POD_NAMESPACE", Value: os.Getenv("POD_NAMESPACE")},
-								{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
-									FieldRef: &corev1.ObjectFieldSelector{
-										FieldPath: "metadata.name"},
-								}},
-							},
+							Args:    args,
+							Env:     []corev1.EnvVar{podNameEnv()},
 							Ports: []corev1.ContainerPort{
 								{ContainerPort: 9443},
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
@@ -102,7 +139,7 @@ func CreateDeployment(class overcommit.OvercommitClass) *appsv1.Deployment {
 							Name: "webhook-tls-secret",
 							VolumeSource: corev1.VolumeSource{
 								Secret: &corev1.SecretVolumeSource{
-									SecretName: class.Name + "-webhook-secret",
+									SecretName: secretName,
 								},
 							},
 						},
@@ -118,11 +155,12 @@ func resourceMustParse(value string) resource.Quantity {
 	return res
 }
 
-func CreateService(name string) *corev1.Service {
+// CreateService returns the service of the webhook deployment of a class in the namespace.
+func CreateService(name, namespace string) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name + "-webhook-service",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: namespace,
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: map[string]string{"app": name + "-overcommit-webhook"},
@@ -136,11 +174,12 @@ func CreateService(name string) *corev1.Service {
 	}
 }
 
+// CreateCertificate returns the certificate of the service of the webhook deployment of a class.
 func CreateCertificate(name string, svc corev1.Service) *certmanager.Certificate {
 	return &certmanager.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name + "-webhook-certificate",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: svc.Namespace,
 		},
 		Spec: certmanager.CertificateSpec{
 			SecretName: name + "-webhook-secret",
@@ -162,35 +201,164 @@ func CreateCertificate(name string, svc corev1.Service) *certmanager.Certificate
 	}
 }
 
-func getSelectorClassNotExist(label string) *metav1.LabelSelector {
-	return &metav1.LabelSelector{
-		MatchExpressions: []metav1.LabelSelectorRequirement{
-			{
-				Key:      label,
-				Operator: metav1.LabelSelectorOpDoesNotExist,
+// GeneratePodMutatingService returns the service of the shared pod mutating webhook deployment.
+func GeneratePodMutatingService(deployment appsv1.Deployment) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name + "-service",
+			Namespace: deployment.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"app": deployment.Name,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "https",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       443,
+					TargetPort: intstr.FromInt(9443),
+				},
 			},
 		},
 	}
 }
 
+// GenerateCertificateMutatingPods returns the certificate of the shared pod mutating webhook
+// deployment.
+func GenerateCertificateMutatingPods(issuer certmanager.Issuer, svc corev1.Service) *certmanager.Certificate {
+	return &certmanager.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-mutating-webhook",
+			Namespace: svc.Namespace,
+		},
+		Spec: certmanager.CertificateSpec{
+			SecretName: "pod-mutating-webhook",
+			IssuerRef: certmanagermeta.ObjectReference{
+				Name: issuer.Name,
+			},
+			Duration: &metav1.Duration{
+				Duration: 365 * 24 * time.Hour,
+			},
+			RenewBefore: &metav1.Duration{
+				Duration: 30 * 24 * time.Hour,
+			},
+			DNSNames: []string{
+				svc.Name + "." + svc.Namespace + ".svc",
+				svc.Name + "." + svc.Namespace + ".svc.cluster.local",
+			},
+		},
+	}
+}
+
+// getSelectorClassNotExist selects the pods without any of the labels.
+func getSelectorClassNotExist(labels []string) *metav1.LabelSelector {
+	selector := &metav1.LabelSelector{}
+	for _, label := range labels {
+		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      label,
+			Operator: metav1.LabelSelectorOpDoesNotExist,
+		})
+	}
+	return selector
+}
+
+// ExcludeNamespacesMatchCondition is the name of the match condition generated from the
+// excludedNamespaces regex of a class.
+const ExcludeNamespacesMatchCondition = "exclude-namespaces"
+
 func getMatchCondition(isDefault bool, name string, excludedNamespaces string, label string) []admissionv1.MatchCondition {
 	matchConditions := []admissionv1.MatchCondition{}
+	if excludedNamespaces == "" {
+		return matchConditions
+	}
 	matchConditions = append(matchConditions, admissionv1.MatchCondition{
-		Name:       "exclude-namespaces",
-		Expression: "!object.metadata.namespace.matches('" + excludedNamespaces + "')",
+		Name:       ExcludeNamespacesMatchCondition,
+		Expression: "!object.metadata.namespace.matches(" + celString(excludedNamespaces) + ")",
 	})
 
 	return matchConditions
 }
 
-func getObjectSelector(isDefault bool, label string, name string) *metav1.LabelSelector {
+// OvercommitLabelMatchCondition is the name of the match condition selecting the pods of a class
+// by several labels during a label migration.
+const OvercommitLabelMatchCondition = "overcommit-label"
+
+// getClassMatchConditions returns the generated match conditions followed by the ones of the class.
+func getClassMatchConditions(class overcommit.OvercommitClass, isDefault bool, labels []string) []admissionv1.MatchCondition {
+	matchConditions := getMatchCondition(isDefault, class.Name, class.Spec.ExcludedNamespaces, labels[0])
+	if !isDefault && len(labels) > 1 {
+		matchConditions = append(matchConditions, admissionv1.MatchCondition{
+			Name:       OvercommitLabelMatchCondition,
+			Expression: labelsMatchExpression(labels, class.Name),
+		})
+	}
+	return append(matchConditions, class.Spec.MatchConditions...)
+}
+
+// labelsMatchExpression returns a CEL expression matching the pods whose first label set among
+// labels names the class, so a pod labeled with both the previous and the new label is only
+// mutated by the class of the new one.
+func labelsMatchExpression(labels []string, name string) string {
+	expression := "false"
+	for i := len(labels) - 1; i >= 0; i-- {
+		label := celString(labels[i])
+		expression = fmt.Sprintf("(%s in object.metadata.labels ? object.metadata.labels[%s] == %s : %s)", label, label, celString(name), expression)
+	}
+	return "has(object.metadata.labels) && " + expression
+}
+
+// celString returns value as a double-quoted CEL string literal, escaping backslashes,
+// quotes and control characters so the value can't change the expression it is pasted in.
+func celString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, "\\u%04x", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// getNamespaceSelector returns the namespaceSelector of the class, excluding the
+// namespaces in excludedNamespaceNames through their kubernetes.io/metadata.name label.
+func getNamespaceSelector(class overcommit.OvercommitClass) *metav1.LabelSelector {
+	selector := &metav1.LabelSelector{}
+	if class.Spec.NamespaceSelector != nil {
+		selector = class.Spec.NamespaceSelector.DeepCopy()
+	}
+	if len(class.Spec.ExcludedNamespaceNames) > 0 {
+		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      corev1.LabelMetadataName,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   class.Spec.ExcludedNamespaceNames,
+		})
+	}
+	return selector
+}
+
+// getObjectSelector selects the pods without any of the labels for the default class, and the
+// pods labeled with the class otherwise. When several labels are honored the pods of the class
+// are selected by the OvercommitLabelMatchCondition instead, as a selector can't match any of
+// them.
+func getObjectSelector(isDefault bool, labels []string, name string) *metav1.LabelSelector {
 	if isDefault {
-		return getSelectorClassNotExist(label)
+		return getSelectorClassNotExist(labels)
+	} else if len(labels) > 1 {
+		return &metav1.LabelSelector{}
 	} else {
 		return &metav1.LabelSelector{
 			MatchExpressions: []metav1.LabelSelectorRequirement{
 				{
-					Key:      label,
+					Key:      labels[0],
 					Operator: metav1.LabelSelectorOpIn,
 					Values:   []string{name},
 				},
@@ -199,9 +367,27 @@ func getObjectSelector(isDefault bool, label string, name string) *metav1.LabelS
 	}
 }
 
-func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc corev1.Service, cert certmanager.Certificate, label string) *admissionv1.MutatingWebhookConfiguration {
+// withPodSelector adds the requirements of the podSelector of the class to the selector.
+func withPodSelector(selector *metav1.LabelSelector, class overcommit.OvercommitClass) *metav1.LabelSelector {
+	if class.Spec.PodSelector == nil {
+		return selector
+	}
+	podSelector := class.Spec.PodSelector.DeepCopy()
+	selector.MatchExpressions = append(selector.MatchExpressions, podSelector.MatchExpressions...)
+	for key, value := range podSelector.MatchLabels {
+		if selector.MatchLabels == nil {
+			selector.MatchLabels = map[string]string{}
+		}
+		selector.MatchLabels[key] = value
+	}
+	return selector
+}
+
+// CreateMutatingWebhookConfiguration returns the MutatingWebhookConfiguration of the class,
+// calling the webhook behind svc on path for the pods selected by labels, see
+// Overcommit.OvercommitLabels.
+func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc corev1.Service, cert certmanager.Certificate, labels []string, path string) *admissionv1.MutatingWebhookConfiguration {
 
-	var path = "/mutate--v1-pod"
 	var scope = admissionv1.NamespacedScope
 	var policy = admissionv1.Fail
 	var sideEffect = admissionv1.SideEffectClassNone
@@ -239,8 +425,9 @@ func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc co
 				AdmissionReviewVersions: []string{"v1"},
 				FailurePolicy:           &policy,
 				SideEffects:             &sideEffect,
-				MatchConditions:         getMatchCondition(false, class.Name, class.Spec.ExcludedNamespaces, label),
-				ObjectSelector:          getObjectSelector(false, label, class.Name),
+				MatchConditions:         getClassMatchConditions(class, false, labels),
+				NamespaceSelector:       getNamespaceSelector(class),
+				ObjectSelector:          withPodSelector(getObjectSelector(false, labels, class.Name), class),
 			},
 		},
 	}
@@ -271,8 +458,9 @@ func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc co
 			AdmissionReviewVersions: []string{"v1"},
 			FailurePolicy:           &policy,
 			SideEffects:             &sideEffect,
-			MatchConditions:         getMatchCondition(class.Spec.IsDefault, class.Name, class.Spec.ExcludedNamespaces, label),
-			ObjectSelector:          getObjectSelector(class.Spec.IsDefault, label, class.Name),
+			MatchConditions:         getClassMatchConditions(class, class.Spec.IsDefault, labels),
+			NamespaceSelector:       getNamespaceSelector(class),
+			ObjectSelector:          withPodSelector(getObjectSelector(class.Spec.IsDefault, labels, class.Name), class),
 		})
 	}
 	return webhookConfig
@@ -6,21 +6,27 @@
 package resources
 
 import (
-	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	certmanager "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func TestCreateDeployment(t *testing.T) {
-	os.Setenv("POD_NAMESPACE", "test-namespace")
-	os.Setenv("IMAGE_REGISTRY", "test-registry")
-	os.Setenv("IMAGE_REPOSITORY", "test-repo")
-	os.Setenv("APP_VERSION", "v1.0.0")
+var testConfig = config.Config{
+	Role:               config.RoleOperator,
+	Namespace:          "test-namespace",
+	Image:              "test-registry/test-repo:v1.0.0",
+	ServiceAccountName: "k8s-overcommit",
+}
 
+func TestCreateDeployment(t *testing.T) {
 	class := overcommit.OvercommitClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "test-class",
@@ -31,11 +37,18 @@ func TestCreateDeployment(t *testing.T) {
 		},
 	}
 
-	deployment := CreateDeployment(class)
+	deployment := CreateDeployment(class, nil, testConfig)
 
 	if deployment.ObjectMeta.Name != "test-class-overcommit-webhook" {
 		t.Errorf("Expected deployment name 'test-class-overcommit-webhook', got '%s'", deployment.ObjectMeta.Name)
 	}
+	if deployment.Namespace != "test-namespace" || deployment.Spec.Template.Spec.Containers[0].Image != "test-registry/test-repo:v1.0.0" {
+		t.Errorf("Expected the namespace and the image of the configuration, got '%s' and '%s'", deployment.Namespace, deployment.Spec.Template.Spec.Containers[0].Image)
+	}
+	args := deployment.Spec.Template.Spec.Containers[0].Args
+	if !slices.Contains(args, "--role=mutating-webhook") || !slices.Contains(args, "--class-name=test-class") {
+		t.Errorf("Expected the mutating webhook role of the class, got '%v'", args)
+	}
 
 	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 1 {
 		t.Errorf("Expected replicas to be 1, got '%v'", deployment.Spec.Replicas)
@@ -43,9 +56,7 @@ func TestCreateDeployment(t *testing.T) {
 }
 
 func TestCreateService(t *testing.T) {
-	os.Setenv("POD_NAMESPACE", "test-namespace")
-
-	service := CreateService("test-class")
+	service := CreateService("test-class", "test-namespace")
 
 	if service.ObjectMeta.Name != "test-class-webhook-service" {
 		t.Errorf("Expected service name 'test-class-webhook-service', got '%s'", service.ObjectMeta.Name)
@@ -57,8 +68,6 @@ func TestCreateService(t *testing.T) {
 }
 
 func TestCreateCertificate(t *testing.T) {
-	os.Setenv("POD_NAMESPACE", "test-namespace")
-
 	service := corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-service",
@@ -93,3 +102,164 @@ func TestResourceMustParse(t *testing.T) {
 		t.Errorf("Expected '250m', got '%s'", cpu.String())
 	}
 }
+
+func TestGetMatchConditionEscapesTheRegex(t *testing.T) {
+	conditions := getMatchCondition(false, "test-class", `kube-.*') || true || ('"\\`, "label")
+
+	expected := `!object.metadata.namespace.matches("kube-.*') || true || ('\"\\\\")`
+	if len(conditions) != 1 || conditions[0].Expression != expected {
+		t.Errorf("Expected expression '%s', got '%v'", expected, conditions)
+	}
+
+	if conditions := getMatchCondition(false, "test-class", "", "label"); len(conditions) != 0 {
+		t.Errorf("Expected no match conditions without regex, got '%v'", conditions)
+	}
+}
+
+func TestCreateMutatingWebhookConfigurationSelectors(t *testing.T) {
+	class := overcommit.OvercommitClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-class",
+		},
+		Spec: overcommit.OvercommitClassSpec{
+			ExcludedNamespaceNames: []string{"kube-system"},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "payments"},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"tier": "batch"},
+			},
+		},
+	}
+
+	webhookConfig := CreateMutatingWebhookConfiguration(class, corev1.Service{}, certmanager.Certificate{}, []string{"inditex.com/overcommit-class"}, PodMutatingWebhookPath)
+	webhook := webhookConfig.Webhooks[0]
+
+	if webhook.NamespaceSelector.MatchLabels["team"] != "payments" {
+		t.Errorf("Expected namespace selector label 'team=payments', got '%v'", webhook.NamespaceSelector.MatchLabels)
+	}
+	excluded := webhook.NamespaceSelector.MatchExpressions
+	if len(excluded) != 1 || excluded[0].Key != corev1.LabelMetadataName || excluded[0].Operator != metav1.LabelSelectorOpNotIn {
+		t.Errorf("Expected namespace selector to exclude kube-system by name, got '%v'", excluded)
+	}
+	if webhook.ObjectSelector.MatchLabels["tier"] != "batch" || len(webhook.ObjectSelector.MatchExpressions) != 1 {
+		t.Errorf("Expected object selector with the class label and 'tier=batch', got '%v'", webhook.ObjectSelector)
+	}
+	if class.Spec.NamespaceSelector.MatchExpressions != nil {
+		t.Errorf("Expected the namespace selector of the class to be left untouched")
+	}
+}
+
+func TestCreateMutatingWebhookConfigurationMatchConditions(t *testing.T) {
+	class := overcommit.OvercommitClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-class",
+		},
+		Spec: overcommit.OvercommitClassSpec{
+			IsDefault:          true,
+			ExcludedNamespaces: "kube-.*",
+			MatchConditions: []admissionv1.MatchCondition{
+				{Name: "high-priority", Expression: "object.spec.priorityClassName == 'high'"},
+			},
+		},
+	}
+
+	webhookConfig := CreateMutatingWebhookConfiguration(class, corev1.Service{}, certmanager.Certificate{}, []string{"inditex.com/overcommit-class"}, PodMutatingWebhookPath)
+
+	for _, webhook := range webhookConfig.Webhooks {
+		conditions := webhook.MatchConditions
+		if len(conditions) != 2 || conditions[0].Name != ExcludeNamespacesMatchCondition || conditions[1].Name != "high-priority" {
+			t.Errorf("Expected the generated match condition followed by the class one in '%s', got '%v'", webhook.Name, conditions)
+		}
+	}
+}
+
+func TestCreateMutatingWebhookConfigurationLabelMigration(t *testing.T) {
+	class := overcommit.OvercommitClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-class",
+		},
+		Spec: overcommit.OvercommitClassSpec{
+			IsDefault: true,
+		},
+	}
+	labels := []string{"inditex.dev/overcommit-class", "inditex.com/overcommit-class"}
+
+	webhookConfig := CreateMutatingWebhookConfiguration(class, corev1.Service{}, certmanager.Certificate{}, labels, PodMutatingWebhookPath)
+
+	classWebhook := webhookConfig.Webhooks[0]
+	if len(classWebhook.ObjectSelector.MatchExpressions) != 0 {
+		t.Errorf("Expected the pods of the class to be selected by a match condition, got selector '%v'", classWebhook.ObjectSelector)
+	}
+	expected := `has(object.metadata.labels) && ("inditex.dev/overcommit-class" in object.metadata.labels ? ` +
+		`object.metadata.labels["inditex.dev/overcommit-class"] == "test-class" : ("inditex.com/overcommit-class" in object.metadata.labels ? ` +
+		`object.metadata.labels["inditex.com/overcommit-class"] == "test-class" : false))`
+	if len(classWebhook.MatchConditions) != 1 || classWebhook.MatchConditions[0].Name != OvercommitLabelMatchCondition ||
+		classWebhook.MatchConditions[0].Expression != expected {
+		t.Errorf("Expected the match condition %q, got '%v'", expected, classWebhook.MatchConditions)
+	}
+
+	defaultWebhook := webhookConfig.Webhooks[1]
+	if len(defaultWebhook.MatchConditions) != 0 {
+		t.Errorf("Expected no match condition for the default class, got '%v'", defaultWebhook.MatchConditions)
+	}
+	requirements := defaultWebhook.ObjectSelector.MatchExpressions
+	if len(requirements) != 2 || requirements[0].Key != labels[0] || requirements[1].Key != labels[1] ||
+		requirements[1].Operator != metav1.LabelSelectorOpDoesNotExist {
+		t.Errorf("Expected the default class to select the pods without any of the labels, got '%v'", requirements)
+	}
+}
+
+func TestGeneratePodMutatingDeployment(t *testing.T) {
+	overcommitObject := overcommit.Overcommit{
+		Spec: overcommit.OvercommitSpec{
+			Labels: map[string]string{"key": "value"},
+		},
+	}
+
+	deployment := GeneratePodMutatingDeployment(overcommitObject, testConfig)
+
+	if deployment.ObjectMeta.Name != PodMutatingWebhookName {
+		t.Errorf("Expected deployment name '%s', got '%s'", PodMutatingWebhookName, deployment.ObjectMeta.Name)
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 2 {
+		t.Errorf("Expected replicas to be 2, got '%v'", deployment.Spec.Replicas)
+	}
+	if deployment.Spec.Template.Labels["key"] != "value" || deployment.Spec.Template.Labels["app"] != PodMutatingWebhookName {
+		t.Errorf("Expected the labels of the Overcommit and the app label, got '%v'", deployment.Spec.Template.Labels)
+	}
+	for _, arg := range deployment.Spec.Template.Spec.Containers[0].Args {
+		if strings.HasPrefix(arg, "--class-name") {
+			t.Errorf("Expected no class name in the shared deployment, got '%s'", arg)
+		}
+	}
+
+	replicas := int32(3)
+	overcommitObject.Spec.MutatingWebhook = &overcommit.MutatingWebhookSpec{Topology: overcommit.WebhookTopologyConsolidated, Replicas: &replicas}
+	deployment = GeneratePodMutatingDeployment(overcommitObject, testConfig)
+	if *deployment.Spec.Replicas != 3 {
+		t.Errorf("Expected replicas to be 3, got '%v'", *deployment.Spec.Replicas)
+	}
+}
+
+func TestCreateMutatingWebhookConfigurationPath(t *testing.T) {
+	class := overcommit.OvercommitClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-class"},
+		Spec:       overcommit.OvercommitClassSpec{IsDefault: true},
+	}
+	service := corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: PodMutatingWebhookName + "-service", Namespace: "test-namespace"}}
+
+	webhookConfig := CreateMutatingWebhookConfiguration(class, service, certmanager.Certificate{}, []string{"inditex.com/overcommit-class"}, ClassWebhookPath(class.Name))
+
+	if len(webhookConfig.Webhooks) != 2 {
+		t.Fatalf("Expected 2 webhooks, got %d", len(webhookConfig.Webhooks))
+	}
+	for _, webhook := range webhookConfig.Webhooks {
+		if path := *webhook.ClientConfig.Service.Path; path != "/mutate--v1-pod/test-class" {
+			t.Errorf("Expected path '/mutate--v1-pod/test-class', got '%s'", path)
+		}
+		if webhook.ClientConfig.Service.Name != service.Name {
+			t.Errorf("Expected service '%s', got '%s'", service.Name, webhook.ClientConfig.Service.Name)
+		}
+	}
+}
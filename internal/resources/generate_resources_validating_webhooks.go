@@ -6,15 +6,17 @@
 package resources
 
 import (
-	"os"
+	"strings"
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certmanagermeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -23,7 +25,7 @@ func GenerateCertificateValidatingPods(issuer certmanagerv1.Issuer, svc corev1.S
 	return &certmanagerv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "pod-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: svc.Namespace,
 		},
 		Spec: certmanagerv1.CertificateSpec{
 			SecretName: "pod-validating-webhook",
@@ -44,17 +46,17 @@ func GenerateCertificateValidatingPods(issuer certmanagerv1.Issuer, svc corev1.S
 	}
 }
 
-func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *appsv1.Deployment {
+func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit, cfg config.Config) *appsv1.Deployment {
 	replicas := int32(1)
 	labels := overcommitObject.Spec.Labels
 	if labels == nil {
 		labels = make(map[string]string)
 	}
 	labels["app"] = "k8s-overcommit-pod-validating-webhook"
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-pod-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.Namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
@@ -69,52 +71,13 @@ func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *ap
 					Annotations: overcommitObject.Spec.Annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:  "k8s-overcommit-pod-validating-webhook",
-							Image: os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
-							Args: []string{
-								"--metrics-bind-address=:8080",
-								"-metrics-secure=false",
-							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "ENABLE_POD_VALIDATING_WEBHOOK",
-									Value: "true",
-								},
-								{
-									Name:  "IMAGE_REGISTRY",
-									Value: os.Getenv("IMAGE_REGISTRY"),
-								},
-								{
-									Name:  "IMAGE_REPOSITORY",
-									Value: os.Getenv("IMAGE_REPOSITORY"),
-								},
-								{
-									Name:  "APP_VERSION",
-									Value: os.Getenv("APP_VERSION"),
-								},
-								{
-									Name:  "WEBHOOK_CERT_DIR",
-									Value: "/etc/webhook/config",
-								},
-								{
-									Name:  "SERVICE_ACCOUNT_NAME",
-									Value: os.Getenv("SERVICE_ACCOUNT_NAME"),
-								},
-								{
-									Name:  "POD_NAMESPACE",
-									Value: os.Getenv("POD_NAMESPACE"),
-								},
-								{
-									Name: "POD_NAME",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											FieldPath: "metadata.name"},
-									},
-								},
-							},
+							Image: cfg.Image,
+							Args:  roleArgs(cfg, config.RoleValidatingWebhook, "--cert-dir=/etc/webhook/config"),
+							Env:   []corev1.EnvVar{podNameEnv()},
 							Ports: []corev1.ContainerPort{
 								{ContainerPort: 9443},
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
@@ -142,6 +105,8 @@ func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *ap
 			},
 		},
 	}
+	applyDeploymentTemplate(deployment, overcommitObject.Spec.Deployment)
+	return deployment
 }
 
 func GeneratePodValidatingService(deployment appsv1.Deployment) *corev1.Service {
@@ -166,7 +131,9 @@ func GeneratePodValidatingService(deployment appsv1.Deployment) *corev1.Service
 	}
 }
 
-func GeneratePodValidatingWebhookConfiguration(deployment appsv1.Deployment, service corev1.Service, certificate certmanagerv1.Certificate, label string) *admissionv1.ValidatingWebhookConfiguration {
+// GeneratePodValidatingWebhookConfiguration returns the ValidatingWebhookConfiguration of the
+// pods with any of the labels, see Overcommit.OvercommitLabels.
+func GeneratePodValidatingWebhookConfiguration(deployment appsv1.Deployment, service corev1.Service, certificate certmanagerv1.Certificate, labels []string) *admissionv1.ValidatingWebhookConfiguration {
 	var policy = admissionv1.Fail
 	var sideEffects = admissionv1.SideEffectClassNone
 	var path = "/validate--v1-pod"
@@ -200,36 +167,61 @@ func GeneratePodValidatingWebhookConfiguration(deployment appsv1.Deployment, ser
 				FailurePolicy:           &policy,
 				SideEffects:             &sideEffects,
 				AdmissionReviewVersions: []string{"v1"},
-				ObjectSelector: &metav1.LabelSelector{
-					MatchExpressions: []metav1.LabelSelectorRequirement{
-						{
-							Key:      label,
-							Operator: metav1.LabelSelectorOpExists,
-						},
-					},
-				},
-				MatchConditions: []admissionv1.MatchCondition{
+				ObjectSelector:          podValidatingObjectSelector(labels),
+				MatchConditions: append([]admissionv1.MatchCondition{
 					{
 						Name:       "exclude-operator-namespace",
-						Expression: "!object.metadata.namespace.matches('" + os.Getenv("POD_NAMESPACE") + "')",
+						Expression: "!object.metadata.namespace.matches(" + celString(service.Namespace) + ")",
 					},
-				},
+				}, podValidatingMatchConditions(labels)...),
+			},
+		},
+	}
+}
+
+// podValidatingObjectSelector selects the pods with the label. When several labels are honored
+// the pods are selected by the OvercommitLabelMatchCondition instead, as a selector can't match
+// any of them.
+func podValidatingObjectSelector(labels []string) *metav1.LabelSelector {
+	if len(labels) > 1 {
+		return &metav1.LabelSelector{}
+	}
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      labels[0],
+				Operator: metav1.LabelSelectorOpExists,
 			},
 		},
 	}
 }
 
-func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Overcommit) *appsv1.Deployment {
+// podValidatingMatchConditions selects the pods with any of the labels when several are honored.
+func podValidatingMatchConditions(labels []string) []admissionv1.MatchCondition {
+	if len(labels) < 2 {
+		return nil
+	}
+	exists := make([]string, 0, len(labels))
+	for _, label := range labels {
+		exists = append(exists, celString(label)+" in object.metadata.labels")
+	}
+	return []admissionv1.MatchCondition{{
+		Name:       OvercommitLabelMatchCondition,
+		Expression: "has(object.metadata.labels) && (" + strings.Join(exists, " || ") + ")",
+	}}
+}
+
+func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Overcommit, cfg config.Config) *appsv1.Deployment {
 	replicas := int32(1)
 	labels := overcommitObject.Spec.Labels
 	if labels == nil {
 		labels = make(map[string]string)
 	}
 	labels["app"] = "k8s-overcommit-class-validating-webhook"
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-class-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.Namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
@@ -244,48 +236,13 @@ func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Ove
 					Annotations: overcommitObject.Spec.Annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:  "k8s-overcommit-class-validating-webhook",
-							Image: os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
-							Args: []string{
-								"--metrics-bind-address=:8080",
-								"-metrics-secure=false",
-							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "ENABLE_OC_VALIDATING_WEBHOOK",
-									Value: "true",
-								},
-								{
-									Name:  "IMAGE_REGISTRY",
-									Value: os.Getenv("IMAGE_REGISTRY"),
-								},
-								{
-									Name:  "IMAGE_REPOSITORY",
-									Value: os.Getenv("IMAGE_REPOSITORY"),
-								},
-								{
-									Name:  "APP_VERSION",
-									Value: os.Getenv("APP_VERSION"),
-								},
-								{
-									Name:  "WEBHOOK_CERT_DIR",
-									Value: "/etc/webhook/config",
-								},
-								{
-									Name: "POD_NAME",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											FieldPath: "metadata.name"},
-									},
-								},
-								{
-									Name:  "POD_NAMESPACE",
-									Value: os.Getenv("POD_NAMESPACE"),
-								},
-							},
+							Image: cfg.Image,
+							Args:  roleArgs(cfg, config.RoleClassWebhook, "--cert-dir=/etc/webhook/config"),
+							Env:   []corev1.EnvVar{podNameEnv()},
 							Ports: []corev1.ContainerPort{
 								{ContainerPort: 9443},
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
@@ -313,6 +270,8 @@ func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Ove
 			},
 		},
 	}
+	applyDeploymentTemplate(deployment, overcommitObject.Spec.Deployment)
+	return deployment
 }
 
 func GenerateOvercommitClassValidatingService(deployment appsv1.Deployment) *corev1.Service {
@@ -341,7 +300,7 @@ func GenerateCertificateValidatingOvercommitClass(issuer certmanagerv1.Issuer, s
 	return &certmanagerv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "oc-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: svc.Namespace,
 		},
 		Spec: certmanagerv1.CertificateSpec{
 			SecretName: "oc-validating-webhook",
@@ -401,3 +360,29 @@ func GenerateOvercommitClassValidatingWebhookConfiguration(deployment appsv1.Dep
 		},
 	}
 }
+
+// OvercommitClassCRDName is the name of the OvercommitClass CustomResourceDefinition.
+const OvercommitClassCRDName = "overcommitclasses.overcommit.inditex.dev"
+
+// GenerateOvercommitClassConversion returns the conversion of the OvercommitClass CRD,
+// served by the OvercommitClass validating webhook deployment, and the annotations that
+// make cert-manager inject its CA bundle.
+func GenerateOvercommitClassConversion(service corev1.Service, certificate certmanagerv1.Certificate) (*apiextensionsv1.CustomResourceConversion, map[string]string) {
+	var path = "/convert"
+
+	return &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{
+					Name:      service.Name,
+					Namespace: service.Namespace,
+					Path:      &path,
+				},
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}, map[string]string{
+		"cert-manager.io/inject-ca-from": certificate.Namespace + "/" + certificate.Name,
+	}
+}
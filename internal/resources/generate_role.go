@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// roleArgs returns the arguments of a generated deployment that runs the role, with the
+// configuration of the operator.
+func roleArgs(cfg config.Config, role config.Role, args ...string) []string {
+	return append([]string{
+		"--metrics-bind-address=:8080",
+		"-metrics-secure=false",
+		"--role=" + string(role),
+		"--namespace=" + cfg.Namespace,
+		"--image=" + cfg.Image,
+	}, args...)
+}
+
+// podNameEnv sets the POD_NAME variable the name of the pod defaults to.
+func podNameEnv() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: "POD_NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+		},
+	}
+}
@@ -9,31 +9,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func GetOvercommitLabel(ctx context.Context, k8sClient client.Client) (string, error) {
-	if k8sClient == nil {
-		return "", errors.New("client parameter cannot be nil")
-	}
-
-	// Create a new OvercommitClass object
-	var overcommitObject overcommit.Overcommit
-
-	// Search for the OvercommitClass with the name "cluster"
-	err := k8sClient.Get(ctx, client.ObjectKey{
-		Name: "cluster",
-	}, &overcommitObject)
-
+// GetOvercommitLabels returns the labels selecting the class of pods and namespaces, see
+// Overcommit.OvercommitLabels.
+func GetOvercommitLabels(ctx context.Context, k8sClient client.Client) ([]string, error) {
+	overcommitObject, err := GetOvercommit(ctx, k8sClient)
 	if err != nil {
-		podlog.Error(err, "Error getting the CR cluster of Overcommit", "name", "cluster")
-		return "", fmt.Errorf("error getting OvercommitClass with name '%s': %w", "cluster", err)
+		return nil, err
 	}
-
-	// Return the overcommit label
-	return overcommitObject.Spec.OvercommitLabel, nil
+	return overcommitObject.OvercommitLabels(time.Now()), nil
 }
 
 func GetOvercommit(ctx context.Context, k8sClient client.Client) (overcommit.Overcommit, error) {
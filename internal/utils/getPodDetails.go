@@ -8,7 +8,6 @@ package utils
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -18,14 +17,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func GetPodImageDetails(ctx context.Context, client client.Reader) (string, string, string, error) {
-	podName := os.Getenv("POD_NAME")
-	podNamespace := os.Getenv("POD_NAMESPACE")
-
-	if podName == "" || podNamespace == "" {
-		return "", "", "", fmt.Errorf("POD_NAME or POD_NAMESPACE environment variables are not set")
-	}
-
+// GetPodImageDetails returns the registry, the repository and the tag of the image of the pod.
+func GetPodImageDetails(ctx context.Context, client client.Reader, podNamespace, podName string) (string, string, string, error) {
 	pod := &corev1.Pod{}
 	err := client.Get(ctx, types.NamespacedName{
 		Name:      podName,
@@ -58,14 +51,8 @@ func GetPodImageDetails(ctx context.Context, client client.Reader) (string, stri
 	return "", "", "", fmt.Errorf("no containers found in pod")
 }
 
-func GetPodServiceAccount(client client.Reader) (string, error) {
-	podName := os.Getenv("POD_NAME")
-	podNamespace := os.Getenv("POD_NAMESPACE")
-
-	if podName == "" || podNamespace == "" {
-		return "", fmt.Errorf("POD_NAME or POD_NAMESPACE environment variables are not set")
-	}
-
+// GetPodServiceAccount returns the service account of the pod.
+func GetPodServiceAccount(client client.Reader, podNamespace, podName string) (string, error) {
 	pod := &corev1.Pod{}
 	err := client.Get(context.TODO(), types.NamespacedName{
 		Name:      podName,
@@ -79,14 +66,7 @@ func GetPodServiceAccount(client client.Reader) (string, error) {
 }
 
 // GetPodDeploymentName retrieves the name of the deployment associated with the current pod.
-func GetPodDeploymentName() (string, error) {
-	podName := os.Getenv("POD_NAME")
-	podNamespace := os.Getenv("POD_NAMESPACE")
-
-	if podName == "" || podNamespace == "" {
-		return "", fmt.Errorf("POD_NAME or POD_NAMESPACE environment variables are not set")
-	}
-
+func GetPodDeploymentName(podNamespace, podName string) (string, error) {
 	// Create a new Kubernetes client
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// RequeueAfter returns the resync period, shortened to the end of the grace period of a label
+// migration in progress so the previous label stops being honored on time.
+func RequeueAfter(overcommitObject *overcommit.Overcommit, resyncPeriod time.Duration) time.Duration {
+	migration := overcommitObject.Status.LabelMigration
+	if migration == nil || migration.Phase != overcommit.LabelMigrationInProgress {
+		return resyncPeriod
+	}
+	// A second past the deadline, so that the previous label is no longer honored
+	remaining := time.Until(migration.Deadline.Time) + time.Second
+	if remaining <= 0 || (resyncPeriod > 0 && resyncPeriod < remaining) {
+		return resyncPeriod
+	}
+	return remaining
+}
+
+// LabelMigrationChanged filters the updates of the Overcommit resource that start or complete a
+// label migration. Its progress is ignored.
+func LabelMigrationChanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, ok := e.ObjectOld.(*overcommit.Overcommit)
+			overcommitObject, newOk := e.ObjectNew.(*overcommit.Overcommit)
+			if !ok || !newOk {
+				return false
+			}
+			return migrationKey(old.Status.LabelMigration) != migrationKey(overcommitObject.Status.LabelMigration)
+		},
+	}
+}
+
+func migrationKey(migration *overcommit.LabelMigrationStatus) string {
+	if migration == nil {
+		return ""
+	}
+	return migration.From + "|" + migration.To + "|" + string(migration.Phase) + "|" + migration.Deadline.UTC().String()
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("Label migration", func() {
+	var overcommitObject *overcommit.Overcommit
+
+	BeforeEach(func() {
+		overcommitObject = &overcommit.Overcommit{
+			Status: overcommit.OvercommitStatus{LabelMigration: &overcommit.LabelMigrationStatus{
+				From:     "inditex.com/overcommit-class",
+				To:       "inditex.dev/overcommit-class",
+				Phase:    overcommit.LabelMigrationInProgress,
+				Deadline: metav1.NewTime(time.Now().Add(time.Minute)),
+			}},
+		}
+	})
+
+	It("should requeue at the end of the grace period", func() {
+		Expect(RequeueAfter(overcommitObject, 10*time.Minute)).To(BeNumerically("<=", time.Minute+time.Second))
+		Expect(RequeueAfter(overcommitObject, 10*time.Second)).To(Equal(10 * time.Second))
+
+		overcommitObject.Status.LabelMigration.Phase = overcommit.LabelMigrationCompleted
+		Expect(RequeueAfter(overcommitObject, 10*time.Minute)).To(Equal(10 * time.Minute))
+	})
+
+	It("should filter the start and the completion of a migration only", func() {
+		predicate := LabelMigrationChanged()
+		progressed := overcommitObject.DeepCopy()
+		progressed.Status.LabelMigration.PendingPods = 3
+		Expect(predicate.Update(event.UpdateEvent{ObjectOld: overcommitObject, ObjectNew: progressed})).To(BeFalse())
+
+		completed := progressed.DeepCopy()
+		completed.Status.LabelMigration.Phase = overcommit.LabelMigrationCompleted
+		Expect(predicate.Update(event.UpdateEvent{ObjectOld: progressed, ObjectNew: completed})).To(BeTrue())
+		Expect(predicate.Update(event.UpdateEvent{ObjectOld: &overcommit.Overcommit{}, ObjectNew: overcommitObject})).To(BeTrue())
+	})
+})
@@ -8,16 +8,31 @@ package v1alphav1
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	overcommitv1alphav1 "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	"github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit/resolver"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // nolint:unused
@@ -27,19 +42,64 @@ var podlog = logf.Log.WithName("webhook")
 // PodCustomDefaulter struct is responsible for setting default values on the custom resource of the Kind Pod.
 type PodCustomDefaulter struct {
 	Recorder record.EventRecorder
-	Client   client.Client
+	Resolver resolver.Resolver
+	// ClassName is the class of a webhook deployed for a single class in the PerClass topology.
+	ClassName string
+	// OperatorNamespace holds the pods of the operator, which are never changed.
+	OperatorNamespace string
+	// Client reviews the permission of the pods that opt out.
+	Client client.Client
+
+	// policies are the onError policies of the classes of the webhook last read, applied when
+	// the classes can't be read.
+	policies sync.Map
 }
 
 func (d *PodCustomDefaulter) InjectRecorder(r record.EventRecorder) {
 	d.Recorder = r
 }
 
-func (d *PodCustomDefaulter) InjectClient(c client.Client) {
-	d.Client = c
+func (d *PodCustomDefaulter) InjectResolver(r resolver.Resolver) {
+	d.Resolver = r
 }
 
 var _ webhook.CustomDefaulter = &PodCustomDefaulter{}
 
+// classPathPrefix is the prefix of the paths the shared webhook deployment is called on,
+// followed by the name of the class of the MutatingWebhookConfiguration.
+const classPathPrefix = "/mutate--v1-pod/"
+
+type webhookClassKey struct{}
+
+// webhookClass returns the class whose MutatingWebhookConfiguration called the webhook: the
+// class in the path on the shared deployment, the class of the deployment otherwise. The class
+// applied to the pod is resolved from its labels by Decide.
+func (d *PodCustomDefaulter) webhookClass(ctx context.Context) string {
+	if name, ok := ctx.Value(webhookClassKey{}).(string); ok {
+		return name
+	}
+	return d.ClassName
+}
+
+// onErrorPolicy returns the onError policy of the class of the webhook. When the classes can't be
+// read it is the policy last read, or Deny for a class whose policy was never read, so a class
+// that denies the pods on errors doesn't admit them on the failure the policy is meant for.
+func (d *PodCustomDefaulter) onErrorPolicy(ctx context.Context, classes []overcommitv1alphav1.OvercommitClass, err error) overcommitv1alphav1.OnError {
+	name := d.webhookClass(ctx)
+	if err == nil {
+		policy := overcommit.OnErrorPolicy(classes, name)
+		d.policies.Store(name, policy)
+		return policy
+	}
+	if policy, ok := d.policies.Load(name); ok {
+		return policy.(overcommitv1alphav1.OnError)
+	}
+	if name != "" {
+		return overcommitv1alphav1.OnErrorDeny
+	}
+	return overcommit.OnErrorPolicy(nil, name)
+}
+
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Pod.
 func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	pod, ok := obj.(*corev1.Pod)
@@ -47,21 +107,369 @@ func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) er
 		return fmt.Errorf("expected a Pod object but got %T", obj)
 	}
 
-	// Call the Overcommit function and pass the EventRecorder
-	overcommit.Overcommit(pod, d.Recorder, d.Client)
+	podlog.Info("Mutating Pod", "generateName", pod.GenerateName)
+	metrics.K8sOvercommitOperatorPodsRequestedTotal.WithLabelValues(d.webhookClass(ctx)).Inc()
+	if d.OperatorNamespace != "" && pod.Namespace == d.OperatorNamespace {
+		d.skip(ctx, pod, overcommit.ReasonOperatorPod)
+		return nil
+	}
+
+	// The classes are read first, they hold the onError policy of the class of the webhook
+	classes, err := d.Resolver.Classes(ctx)
+	policy := d.onErrorPolicy(ctx, classes, err)
+	if err != nil {
+		return d.fail(ctx, pod, policy, err)
+	}
+	overcommitObject, err := d.Resolver.Overcommit(ctx)
+	if err != nil {
+		return d.fail(ctx, pod, policy, err)
+	}
+	namespace, err := d.Resolver.Namespace(ctx, pod.Namespace)
+	if err != nil {
+		if denied := d.fail(ctx, pod, policy, err); denied != nil || policy == overcommitv1alphav1.OnErrorAdmitUnchanged {
+			return denied
+		}
+		// Admit decides the overcommit without the labels of the namespace
+		namespace = nil
+	}
+
+	// A pod created from the spec of an overcommitted one is decided from its original requests
+	restoredFrom, err := overcommit.Restore(pod)
+	if err != nil {
+		podlog.Error(err, "Error restoring the original requests", "generateName", pod.GenerateName)
+	}
+	if overcommit.RevertRequested(pod, namespace) {
+		podlog.Info("Overcommit reverted", "generateName", pod.GenerateName, "namespace", pod.Namespace, "restoredFrom", restoredFrom)
+		d.skip(ctx, pod, "reverted")
+		result(ctx).auditAnnotations = map[string]string{"reverted": "true", "restored-from": restoredFrom}
+		return nil
+	}
+
+	labels := overcommitObject.OvercommitLabels(time.Now())
+	decision := overcommit.Decide(pod, namespace, classes, overcommitObject.Spec, labels[1:]...)
+	podlog.Info(
+		"Overcommit decided", "generateName", pod.GenerateName, "class", decision.ClassName(),
+		"source", decision.Source, "requestPolicy", decision.RequestPolicy, "mode", decision.Mode, "mutated", len(decision.Containers), "skipped", len(decision.Skipped),
+		"effectiveBefore", resourcesString(decision.EffectiveBefore), "effectiveAfter", resourcesString(decision.EffectiveAfter),
+	)
+	for _, container := range decision.Containers {
+		if container.Rule != "" {
+			podlog.Info("Container rule applied", "generateName", pod.GenerateName, "container", container.Name, "initContainer", container.InitContainer, "sidecar", container.Sidecar, "rule", container.Rule)
+		}
+		for _, clamp := range container.Clamps {
+			podlog.Info(
+				"Request clamped", "generateName", pod.GenerateName, "container", container.Name,
+				"resource", clamp.Resource, "reason", clamp.Reason, "calculated", clamp.Calculated.String(),
+			)
+		}
+	}
+	for _, container := range decision.Skipped {
+		if container.Rule != "" {
+			podlog.Info(
+				"Container skipped", "generateName", pod.GenerateName, "container", container.Name,
+				"initContainer", container.InitContainer, "rule", container.Rule, "reason", container.Reason,
+			)
+		}
+	}
+	if decision.Class == nil {
+		d.skip(ctx, pod, "no class found")
+		return nil
+	}
+	if excluded, err := d.excluded(ctx, pod, decision.Class); err != nil {
+		return d.fail(ctx, pod, policy, err)
+	} else if excluded != "" {
+		podlog.Info("Pod excluded", "generateName", pod.GenerateName, "class", decision.ClassName(), "reason", excluded)
+		d.skip(ctx, pod, excluded)
+		return nil
+	}
+
+	if decision.Audited() {
+		if err := overcommit.Audit(pod, decision); err != nil {
+			return d.fail(ctx, pod, policy, err)
+		}
+		d.recordAudit(pod, decision)
+		return nil
+	}
+
+	if decision.Mutated() {
+		annotations, err := overcommit.Record(decision)
+		if err != nil {
+			return d.fail(ctx, pod, policy, err)
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		maps.Copy(pod.Annotations, annotations)
+		result(ctx).auditAnnotations = overcommit.AuditAnnotations(annotations)
+	}
+	overcommit.Apply(pod, decision)
+	d.record(ctx, pod, decision)
+	return nil
+}
+
+// skip records a pod left untouched for the reason.
+func (d *PodCustomDefaulter) skip(ctx context.Context, pod *corev1.Pod, reason string) {
+	metrics.K8sOvercommitOperatorPodsNotMutatedTotal.WithLabelValues(
+		d.webhookClass(ctx), pod.GenerateName, pod.Namespace, reason,
+	).Inc()
+}
+
+// excluded returns the reason the pod is left untouched by its class: an allowed opt-out or an
+// exclusion of the class. An opt-out that isn't allowed denies the pod.
+func (d *PodCustomDefaulter) excluded(ctx context.Context, pod *corev1.Pod, class *overcommitv1alphav1.OvercommitClass) (string, error) {
+	if overcommit.OptedOut(pod) {
+		allowed, err := d.optOutAllowed(ctx, pod, class)
+		if err != nil {
+			return "", err
+		}
+		if !allowed {
+			subject := "the user that creates the pod"
+			if overcommit.ServiceAccountOptOut(class) {
+				subject += " or its service account"
+			}
+			return "", denied("OptOutForbidden", fmt.Sprintf(
+				"the %s annotation requires the %q verb on the OvercommitClass %s, for %s",
+				overcommit.OptOutAnnotation, overcommit.OptOutVerb, class.Name, subject,
+			))
+		}
+		return overcommit.ReasonOptOut, nil
+	}
+
+	ownerKind := ""
+	if overcommit.NeedsOwner(pod, class) {
+		_, kind, err := d.Resolver.PodOwner(ctx, pod)
+		if err != nil {
+			return "", err
+		}
+		ownerKind = kind
+	}
+	return overcommit.Excluded(pod, class, ownerKind), nil
+}
+
+// optOutAllowed tells whether the user that creates the pod is allowed to opt the pods of the
+// namespace out of the class, or the service account of the pod when the class honors it.
+func (d *PodCustomDefaulter) optOutAllowed(ctx context.Context, pod *corev1.Pod, class *overcommitv1alphav1.OvercommitClass) (bool, error) {
+	var subjects []authorizationv1.SubjectAccessReviewSpec
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		user := authorizationv1.SubjectAccessReviewSpec{User: req.UserInfo.Username, Groups: req.UserInfo.Groups, UID: req.UserInfo.UID}
+		for key, values := range req.UserInfo.Extra {
+			if user.Extra == nil {
+				user.Extra = map[string]authorizationv1.ExtraValue{}
+			}
+			user.Extra[key] = authorizationv1.ExtraValue(values)
+		}
+		subjects = append(subjects, user)
+	}
+	if overcommit.ServiceAccountOptOut(class) {
+		serviceAccount := pod.Spec.ServiceAccountName
+		if serviceAccount == "" {
+			serviceAccount = "default"
+		}
+		subjects = append(subjects, authorizationv1.SubjectAccessReviewSpec{
+			User:   serviceaccount.MakeUsername(pod.Namespace, serviceAccount),
+			Groups: serviceaccount.MakeGroupNames(pod.Namespace),
+		})
+	}
+
+	for _, subject := range subjects {
+		subject.ResourceAttributes = &authorizationv1.ResourceAttributes{
+			Namespace: pod.Namespace,
+			Verb:      overcommit.OptOutVerb,
+			Group:     overcommitv1alphav1.GroupVersion.Group,
+			Resource:  "overcommitclasses",
+			Name:      class.Name,
+		}
+		review := &authorizationv1.SubjectAccessReview{Spec: subject}
+		if err := d.Client.Create(ctx, review); err != nil {
+			return false, overcommit.NewResolutionError(overcommit.FailureAuthorizationUnavailable, fmt.Errorf("error reviewing the opt-out of %s: %w", subject.User, err))
+		}
+		if review.Status.Allowed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// recordAudit emits the metrics and the event of an audited decision.
+func (d *PodCustomDefaulter) recordAudit(pod *corev1.Pod, decision overcommit.Decision) {
+	metrics.K8sOvercommitOperatorAuditedPodsTotal.WithLabelValues(decision.ClassName()).Inc()
+
+	savings := overcommit.Savings(decision)
+	for name, quantity := range savings {
+		metrics.K8sOvercommitOperatorAuditRequestSavingsTotal.WithLabelValues(decision.ClassName(), string(name)).Add(quantity.AsApproximateFloat64())
+	}
+	saved := resourcesString(savings)
+	if saved == "" {
+		saved = "nothing"
+	}
+
+	d.Recorder.Eventf(
+		pod,
+		corev1.EventTypeNormal,
+		"OvercommitAudited",
+		"Audited overcommit of Pod '%s': OvercommitClass = %s would lower the requests by %s, see the %s annotation",
+		pod.Name,
+		decision.ClassName(),
+		saved,
+		overcommit.AuditRequestsAnnotation,
+	)
+}
+
+// resourcesString writes the quantities sorted by resource, e.g. "cpu=500m, memory=1Gi".
+func resourcesString(list corev1.ResourceList) string {
+	names := make([]string, 0, len(list))
+	for name := range list {
+		names = append(names, string(name))
+	}
+	slices.Sort(names)
+	quantities := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := list[corev1.ResourceName(name)]
+		quantities = append(quantities, name+"="+quantity.String())
+	}
+	return strings.Join(quantities, ", ")
+}
+
+// record emits the metrics and the event of an applied decision.
+func (d *PodCustomDefaulter) record(ctx context.Context, pod *corev1.Pod, decision overcommit.Decision) {
+	ownerName, ownerKind, err := d.Resolver.PodOwner(ctx, pod)
+	if err != nil {
+		podlog.Error(err, "Error getting the pod owner")
+	}
+	classLabel := decision.ClassName()
+	if decision.Source == overcommit.SourceDefault {
+		classLabel = "default"
+	}
+	metrics.K8sOvercommitPodMutated.WithLabelValues(classLabel, ownerKind, ownerName, pod.Namespace).Inc()
+
+	metrics.K8sOvercommitOperatorMutatedPodsTotal.WithLabelValues(d.webhookClass(ctx)).Inc()
+	if !decision.Mutated() && len(decision.Skipped) > 0 {
+		metrics.K8sOvercommitOperatorPodsNotMutatedTotal.WithLabelValues(
+			d.webhookClass(ctx), pod.GenerateName, pod.Namespace, decision.Skipped[0].Reason,
+		).Inc()
+	}
+
+	// Add an event to the pod
+	d.Recorder.Eventf(
+		pod,
+		corev1.EventTypeNormal,
+		"OvercommitApplied",
+		"Applied overcommit to containers of Pod '%s': OvercommitClass = %s, CPU Overcommit = %.2f, Memory Overcommit = %.2f, effective requests %s -> %s",
+		pod.Name,
+		decision.ClassName(),
+		decision.Class.Spec.CpuOvercommit,
+		decision.Class.Spec.MemoryOvercommit,
+		resourcesString(decision.EffectiveBefore),
+		resourcesString(decision.EffectiveAfter),
+	)
+}
+
+// admissionResult is what Default reports in the admission response through resultHandler:
+// the failure of a pod admitted although its overcommit couldn't be decided, and the
+// annotations for the audit log of the API server.
+type admissionResult struct {
+	reason           overcommit.FailureReason
+	message          string
+	auditAnnotations map[string]string
+}
+
+type admissionResultKey struct{}
+
+// result returns the result of the admission of the context, discarded when there is none.
+func result(ctx context.Context) *admissionResult {
+	if result, ok := ctx.Value(admissionResultKey{}).(*admissionResult); ok {
+		return result
+	}
+	return &admissionResult{}
+}
+
+// fail applies the onError policy to a pod whose overcommit couldn't be decided. It returns the
+// error that denies the pod with the Deny policy, and records the failure for the response of
+// the admission otherwise.
+func (d *PodCustomDefaulter) fail(ctx context.Context, pod *corev1.Pod, policy overcommitv1alphav1.OnError, err error) error {
+	reason := overcommit.Reason(err)
+	podlog.Error(err, "Error deciding the overcommit", "generateName", pod.GenerateName, "namespace", pod.Namespace, "reason", reason, "onError", policy)
+	metrics.K8sOvercommitOperatorResolutionFailuresTotal.WithLabelValues(d.webhookClass(ctx), string(reason), string(policy)).Inc()
+
+	if policy == overcommitv1alphav1.OnErrorDeny {
+		return denied(string(reason), fmt.Sprintf("the overcommit of the pod can't be decided: %v", err))
+	}
+	result := result(ctx)
+	result.reason = reason
+	result.message = err.Error()
 	return nil
 }
 
+// denied returns the error that denies the pod with the reason code.
+func denied(reason, message string) error {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  metav1.StatusReason(reason),
+		Message: message,
+	}}
+}
+
+// resultHandler sets the result recorded by Default on the response of the admission.
+type resultHandler struct {
+	admission.Handler
+}
+
+func (h resultHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	result := &admissionResult{}
+	response := h.Handler.Handle(context.WithValue(ctx, admissionResultKey{}, result), req)
+	if !response.Allowed {
+		return response
+	}
+	response.AuditAnnotations = result.auditAnnotations
+	if result.reason != "" {
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusSuccess,
+			Code:    http.StatusOK,
+			Reason:  metav1.StatusReason(result.reason),
+			Message: result.message,
+		}
+		response.Warnings = append(response.Warnings, fmt.Sprintf("the overcommit of the pod couldn't be fully decided (%s): %s", result.reason, result.message))
+	}
+	return response
+}
+
+// podWebhook returns the webhook that defaults the pods with the defaulter.
+func podWebhook(scheme *runtime.Scheme, defaulter *PodCustomDefaulter) *admission.Webhook {
+	podWebhook := admission.WithCustomDefaulter(scheme, &corev1.Pod{}, defaulter)
+	podWebhook.Handler = resultHandler{podWebhook.Handler}
+	return podWebhook
+}
+
 // +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=mutating-pod-v1.overcommit.inditex.dev,admissionReviewVersions=v1
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// SetupPodWebhookWithManager registers the webhook for Pod in the manager, on the path of the
+// PerClass topology and on the paths of the classes of the Consolidated topology. The class of
+// the configuration is the class of a webhook deployed for a single class.
+// The overcommit values are resolved from an informer-backed cache, and the
+// pod is reported as not ready until that cache is synced.
+func SetupPodWebhookWithManager(mgr ctrl.Manager, cfg config.Config) error {
+	resolutionCache := resolver.NewResolutionCache(mgr.GetCache())
+	if err := mgr.Add(resolutionCache); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("resolution-cache", resolutionCache.ReadyCheck); err != nil {
+		return err
+	}
 
-// SetupPodWebhookWithManager registers the webhook for Pod in the manager.
-func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
-	defaulter := &PodCustomDefaulter{}
+	defaulter := &PodCustomDefaulter{ClassName: cfg.ClassName, OperatorNamespace: cfg.Namespace, Client: mgr.GetClient()}
 	defaulter.InjectRecorder(mgr.GetEventRecorderFor("pod-defaulter"))
-	defaulter.InjectClient(mgr.GetClient())
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(&corev1.Pod{}).
-		WithDefaulter(defaulter).
-		Complete()
+	defaulter.InjectResolver(resolutionCache)
+
+	mgr.GetWebhookServer().Register("/mutate--v1-pod", podWebhook(mgr.GetScheme(), defaulter))
+
+	// Every class of the Consolidated topology calls the webhook on its own path
+	classWebhook := podWebhook(mgr.GetScheme(), defaulter)
+	classWebhook.WithContextFunc = func(ctx context.Context, r *http.Request) context.Context {
+		return context.WithValue(ctx, webhookClassKey{}, strings.TrimPrefix(r.URL.Path, classPathPrefix))
+	}
+	mgr.GetWebhookServer().Register(classPathPrefix, classWebhook)
+	return nil
 }
@@ -7,21 +7,83 @@ package v1alphav1
 
 import (
 	"context"
+	"errors"
+	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	"github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit/resolver"
 )
 
+// failingResolver fails to read the namespaces, the Overcommit resource when overcommitErr is set
+// and the classes when classesErr is set.
+type failingResolver struct {
+	resolver.Resolver
+	overcommitErr bool
+	classesErr    bool
+}
+
+func (r failingResolver) Classes(ctx context.Context) ([]v1.OvercommitClass, error) {
+	if r.classesErr {
+		return nil, overcommit.NewResolutionError(overcommit.FailureClassesUnavailable, errors.New("not synced"))
+	}
+	return r.Resolver.Classes(ctx)
+}
+
+func (r failingResolver) Overcommit(ctx context.Context) (*v1.Overcommit, error) {
+	if r.overcommitErr {
+		return nil, overcommit.NewResolutionError(overcommit.FailureOvercommitUnavailable, errors.New("not found"))
+	}
+	return r.Resolver.Overcommit(ctx)
+}
+
+func (r failingResolver) Namespace(context.Context, string) (*corev1.Namespace, error) {
+	return nil, overcommit.NewResolutionError(overcommit.FailureNamespaceUnavailable, errors.New("not synced"))
+}
+
+// staticResolver resolves a single class.
+type staticResolver struct {
+	resolver.Resolver
+	class v1.OvercommitClass
+}
+
+func (r staticResolver) Classes(context.Context) ([]v1.OvercommitClass, error) {
+	return []v1.OvercommitClass{r.class}, nil
+}
+
+func overcommittedPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-failing-pod",
+			Namespace: "default",
+			Labels:    map[string]string{"inditex.com/overcommit-class": "default-overcommitclass"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "test-container",
+				Image: "nginx",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			}},
+		},
+	}
+}
+
 var _ = Describe("PodCustomDefaulter Webhook", func() {
 	var defaulter *PodCustomDefaulter
 
 	BeforeEach(func() {
-		defaulter = &PodCustomDefaulter{}
-		defaulter.InjectClient(k8sClient)
+		defaulter = &PodCustomDefaulter{ClassName: "default-overcommitclass"}
+		defaulter.InjectResolver(resolution)
 		defaulter.InjectRecorder(recorder)
 	})
 
@@ -70,6 +132,157 @@ var _ = Describe("PodCustomDefaulter Webhook", func() {
 
 			Expect(actualRequests[corev1.ResourceCPU].Equal(expectedRequests[corev1.ResourceCPU])).To(BeTrue())
 			Expect(actualRequests[corev1.ResourceMemory].Equal(expectedRequests[corev1.ResourceMemory])).To(BeTrue())
+			Expect(pod.Annotations).To(HaveKeyWithValue(overcommit.ClassAnnotation, "default-overcommitclass"))
+			Expect(pod.Annotations).To(HaveKeyWithValue(overcommit.OriginalRequestsAnnotation, `{"test-container":{"cpu":"1","memory":"1Gi"}}`))
+		})
+
+		It("Should restore the original requests of a Pod marked for revert", func() {
+			pod := overcommittedPod()
+			Expect(defaulter.Default(context.TODO(), pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(HaveKey(corev1.ResourceCPU))
+
+			result := &admissionResult{}
+			ctx := context.WithValue(context.TODO(), admissionResultKey{}, result)
+			pod.Annotations[overcommit.RevertAnnotation] = "true"
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
+			Expect(pod.Annotations).NotTo(HaveKey(overcommit.ClassAnnotation))
+			Expect(result.auditAnnotations).To(HaveKeyWithValue("restored-from", "default-overcommitclass"))
+		})
+
+		It("Should only annotate a Pod in an audited OvercommitClass", func() {
+			auditClass := &v1.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "audit-overcommitclass",
+				},
+				Spec: v1.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					Mode:             v1.ModeAudit,
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), auditClass)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.TODO(), auditClass)).To(Succeed())
+			}()
+			Eventually(func() ([]v1.OvercommitClass, error) {
+				return resolution.Classes(context.TODO())
+			}).Should(ContainElement(HaveField("Name", "audit-overcommitclass")))
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-audited-pod",
+					Namespace: "default",
+					Labels: map[string]string{
+						"inditex.com/overcommit-class": "audit-overcommitclass",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "test-container",
+							Image: "nginx",
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("1"),
+									corev1.ResourceMemory: resource.MustParse("1Gi"),
+								},
+							},
+						},
+					},
+				},
+			}
+
+			err := defaulter.Default(context.TODO(), pod)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
+			Expect(pod.Annotations).To(HaveKeyWithValue(overcommit.AuditClassAnnotation, "audit-overcommitclass"))
+			Expect(pod.Annotations).To(HaveKeyWithValue(overcommit.AuditRequestsAnnotation, `{"test-container":{"cpu":"500m","memory":"512Mi"}}`))
+		})
+
+		It("Should take the class of the webhook from the path of the shared deployment", func() {
+			ctx := context.WithValue(context.TODO(), webhookClassKey{}, "audit-overcommitclass")
+			Expect(defaulter.webhookClass(ctx)).To(Equal("audit-overcommitclass"))
+			Expect(defaulter.webhookClass(context.TODO())).To(Equal("default-overcommitclass"))
+		})
+
+		It("Should decide without the namespace with the Admit policy", func() {
+			defaulter.InjectResolver(failingResolver{Resolver: resolution})
+			result := &admissionResult{}
+			ctx := context.WithValue(context.TODO(), admissionResultKey{}, result)
+
+			pod := overcommittedPod()
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(HaveKey(corev1.ResourceCPU))
+			Expect(result.reason).To(Equal(overcommit.FailureNamespaceUnavailable))
+		})
+
+		It("Should admit the pod unchanged without the Overcommit resource", func() {
+			defaulter.InjectResolver(failingResolver{Resolver: resolution, overcommitErr: true})
+			result := &admissionResult{}
+			ctx := context.WithValue(context.TODO(), admissionResultKey{}, result)
+
+			pod := overcommittedPod()
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
+			Expect(result.reason).To(Equal(overcommit.FailureOvercommitUnavailable))
+		})
+
+		It("Should deny the pod with the reason of the failure with the Deny policy", func() {
+			err := defaulter.fail(context.TODO(), overcommittedPod(), v1.OnErrorDeny,
+				overcommit.NewResolutionError(overcommit.FailureNamespaceUnavailable, errors.New("not synced")))
+			var statusErr *apierrors.StatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			Expect(statusErr.ErrStatus.Code).To(Equal(int32(http.StatusForbidden)))
+			Expect(statusErr.ErrStatus.Reason).To(Equal(metav1.StatusReason(overcommit.FailureNamespaceUnavailable)))
+		})
+
+		It("Should apply the Deny policy last read when the classes can't be read", func() {
+			classes := staticResolver{Resolver: resolution, class: v1.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-overcommitclass"},
+				Spec:       v1.OvercommitClassSpec{CpuOvercommit: 0.5, MemoryOvercommit: 0.5, OnError: v1.OnErrorDeny},
+			}}
+			defaulter.InjectResolver(classes)
+			Expect(defaulter.Default(context.TODO(), overcommittedPod())).To(Succeed())
+
+			defaulter.InjectResolver(failingResolver{Resolver: classes, classesErr: true})
+			err := defaulter.Default(context.TODO(), overcommittedPod())
+			var statusErr *apierrors.StatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			Expect(statusErr.ErrStatus.Reason).To(Equal(metav1.StatusReason(overcommit.FailureClassesUnavailable)))
+		})
+
+		It("Should deny the pods when the classes were never read", func() {
+			defaulter.InjectResolver(failingResolver{Resolver: resolution, classesErr: true})
+			err := defaulter.Default(context.TODO(), overcommittedPod())
+			var statusErr *apierrors.StatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			Expect(statusErr.ErrStatus.Code).To(Equal(int32(http.StatusForbidden)))
+		})
+
+		It("Should leave the pods of the operator untouched", func() {
+			defaulter.OperatorNamespace = "default"
+
+			pod := overcommittedPod()
+			Expect(defaulter.Default(context.TODO(), pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
+		})
+
+		It("Should leave the pods excluded by their class untouched", func() {
+			defaulter.InjectResolver(staticResolver{Resolver: resolution, class: v1.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-overcommitclass"},
+				Spec: v1.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					Exclusions:       &v1.ExclusionsSpec{PriorityClassNames: []string{"batch"}},
+				},
+			}})
+
+			pod := overcommittedPod()
+			pod.Spec.PriorityClassName = "batch"
+			Expect(defaulter.Default(context.TODO(), pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
 		})
 
 		It("Should fail if the object is not a Pod", func() {
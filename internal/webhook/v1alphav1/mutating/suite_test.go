@@ -19,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -26,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	v1 "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit/resolver"
 )
 
 var (
@@ -34,6 +36,8 @@ var (
 	scheme       = runtime.NewScheme()
 	k8sClient    client.Client
 	recorder     record.EventRecorder
+	resolution   *resolver.ResolutionCache
+	cancel       context.CancelFunc
 	overcommitCR = &v1.Overcommit{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "cluster",
@@ -50,9 +54,6 @@ func TestWebhookSuite(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
-	os.Setenv("POD_NAME", "webhook-server-mock")
-	os.Setenv("POD_NAMESPACE", "webhook-server-mock-namespace")
-	os.Setenv("OVERCOMMIT_CLASS_NAME", "default-overcommitclass")
 	os.Setenv("LABEL_OVERCOMMIT_CLASS", "inditex.com/overcommit-class")
 
 	log.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
@@ -107,15 +108,26 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	recorder = mgr.GetEventRecorderFor("k8s-overcommit-operator")
 	Expect(recorder).NotTo(BeNil())
+
+	By("starting the resolution cache")
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.TODO())
+	informers, err := cache.New(cfg, cache.Options{Scheme: scheme})
+	Expect(err).NotTo(HaveOccurred())
+	go func() {
+		defer GinkgoRecover()
+		Expect(informers.Start(ctx)).To(Succeed())
+	}()
+	resolution = resolver.NewResolutionCache(informers)
+	Expect(resolution.Start(ctx)).To(Succeed())
 })
 
 var _ = AfterSuite(func() {
-	os.Unsetenv("POD_NAME")
-	os.Unsetenv("POD_NAMESPACE")
 	os.Unsetenv("OVERCOMMIT_CLASS_NAME")
 	os.Unsetenv("LABEL_OVERCOMMIT_CLASS")
 
 	By("tearing down the test environment")
+	cancel()
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
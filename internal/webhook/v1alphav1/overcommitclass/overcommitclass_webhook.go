@@ -15,6 +15,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 )
 
 // log is for logging in this package.
@@ -30,12 +32,12 @@ func (v *OvercommitClassValidator) InjectClient(c client.Client) {
 	v.Client = c
 }
 
-// SetupWebhookWithManager will setup the manager to manage the webhooks
-func (r *OvercommitClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetupOvercommitClassWebhookWithManager will setup the manager to manage the webhooks
+func SetupOvercommitClassWebhookWithManager(mgr ctrl.Manager) error {
 	validator := &OvercommitClassValidator{}
 	validator.InjectClient(mgr.GetClient())
 	return ctrl.NewWebhookManagedBy(mgr).
-		For(r).
+		For(&overcommit.OvercommitClass{}).
 		WithValidator(validator).
 		Complete()
 }
@@ -45,7 +47,7 @@ func (r *OvercommitClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (v *OvercommitClassValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 
-	overcommitClass, ok := obj.(*OvercommitClass)
+	overcommitClass, ok := obj.(*overcommit.OvercommitClass)
 	if !ok {
 		return nil, fmt.Errorf("failed to cast object to OvercommitClass")
 	}
@@ -71,6 +73,36 @@ func (v *OvercommitClassValidator) ValidateCreate(ctx context.Context, obj runti
 		return nil, err
 	}
 
+	err = checkSelectors(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkMatchConditions(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBaseline(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkRequestBounds(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkContainerRules(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkInitContainers(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -78,14 +110,14 @@ func (v *OvercommitClassValidator) ValidateCreate(ctx context.Context, obj runti
 func (v *OvercommitClassValidator) ValidateUpdate(ctx context.Context, old runtime.Object, new runtime.Object) (admission.Warnings, error) {
 
 	// Convert old runtime.Object to *OvercommitClass
-	oldOvercommitClass, ok := old.(*OvercommitClass)
+	oldOvercommitClass, ok := old.(*overcommit.OvercommitClass)
 	if !ok {
 		return nil, fmt.Errorf("failed to cast old object to OvercommitClass")
 	}
 	overcommitclasslog.Info("validate update", "name", oldOvercommitClass.Name)
 
 	// Convert new runtime.Object to *OvercommitClass
-	newOvercommitClass, ok := new.(*OvercommitClass)
+	newOvercommitClass, ok := new.(*overcommit.OvercommitClass)
 	if !ok {
 		return nil, fmt.Errorf("failed to cast new object to OvercommitClass")
 	}
@@ -110,12 +142,36 @@ func (v *OvercommitClassValidator) ValidateUpdate(ctx context.Context, old runti
 	if err != nil {
 		return nil, err
 	}
+	err = checkSelectors(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
+	err = checkMatchConditions(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
+	err = checkBaseline(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
+	err = checkRequestBounds(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
+	err = checkContainerRules(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
+	err = checkInitContainers(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (v *OvercommitClassValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
-	overcommitClass, ok := obj.(*OvercommitClass)
+	overcommitClass, ok := obj.(*overcommit.OvercommitClass)
 	if !ok {
 		return nil, fmt.Errorf("failed to cast object to OvercommitClass")
 	}
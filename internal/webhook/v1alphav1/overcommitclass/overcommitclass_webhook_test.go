@@ -0,0 +1,473 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("OvercommitClass Webhook", func() {
+	var validator *OvercommitClassValidator
+
+	BeforeEach(func() {
+		validator = &OvercommitClassValidator{}
+		validator.InjectClient(k8sClient)
+	})
+
+	AfterEach(func() {
+		// Clean up all resources created during the test
+		By("Cleaning up OvercommitClass resources")
+		err := k8sClient.DeleteAllOf(context.TODO(), &overcommit.OvercommitClass{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("ValidateCreate", func() {
+		It("Should pass validation for a valid OvercommitClass", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for invalid CPU overcommit", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      -0.5, // Invalid value
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cpuOvercommit must be greater than 0 and equal or lower than 1, failed creating  class"))
+		})
+
+		It("Should fail validation for invalid memory overcommit", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   -0.5, // Invalid value
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating  class"))
+		})
+
+		It("Should fail validation for invalid excluded namespaces", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: ".**./*/-*./../kube-system,invalid-namespace", // Invalid value
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("regex"))
+		})
+
+		It("Should pass validation for a class without excluded namespaces regex", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-selectors",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:          0.5,
+					MemoryOvercommit:       0.5,
+					ExcludedNamespaceNames: []string{"kube-system"},
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"team": "payments"},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for an invalid pod selector", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					PodSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "app", Operator: "Like"},
+						},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("podSelector"))
+		})
+
+		It("Should pass validation for valid match conditions", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-match-conditions",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					MatchConditions: []admissionv1.MatchCondition{
+						{Name: "high-priority", Expression: "object.spec.priorityClassName == 'high'"},
+						{Name: "multi-container", Expression: "size(object.spec.containers) > 1"},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for a match condition that doesn't compile", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					MatchConditions: []admissionv1.MatchCondition{
+						{Name: "broken", Expression: "object.spec.("},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`matchConditions[0] "broken" is not valid: compilation failed`))
+		})
+
+		It("Should fail validation for a match condition that isn't a boolean", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					MatchConditions: []admissionv1.MatchCondition{
+						{Name: "name", Expression: "object.metadata.name"},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must evaluate to bool"))
+		})
+
+		It("Should fail validation for a match condition with the name of a generated one", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					MatchConditions: []admissionv1.MatchCondition{
+						{Name: "exclude-namespaces", Expression: "true"},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("duplicated"))
+		})
+
+		It("Should fail validation for a match condition with the name of the label migration one", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					MatchConditions: []admissionv1.MatchCondition{
+						{Name: "overcommit-label", Expression: "true"},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("duplicated"))
+		})
+
+		It("Should fail validation for hugepages", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					InitContainers: &overcommit.InitContainersSpec{
+						Ratios: map[corev1.ResourceName]float64{"hugepages-2Mi": 0.5},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("hugepages"))
+		})
+
+		It("Should fail validation for extended resources", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					ContainerRules: []overcommit.ContainerRule{
+						{Name: "gpu", Ratios: map[corev1.ResourceName]float64{"nvidia.com/gpu": 0.5}},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("nvidia.com/gpu can't be overcommitted"))
+		})
+
+		It("Should fail validation for resources with more than 4 decimals", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:              0.5,
+					MemoryOvercommit:           0.5,
+					EphemeralStorageOvercommit: 0.123456,
+					ExcludedNamespaces:         "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("4 decimals max"))
+		})
+
+		It("Should fail validation for a minRequest greater than the maxRequest", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					MinRequest:         corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					MaxRequest:         corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("minRequest"))
+		})
+
+		It("Should fail validation for a baseline of an unsupported resource", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					Baseline: map[corev1.ResourceName]overcommit.BaselineMode{
+						corev1.ResourceMemory: overcommit.BaselineFromRequests,
+						"nvidia.com/gpu":      overcommit.BaselineMax,
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("baseline"))
+		})
+
+		It("Should fail validation for a container rule with an invalid container name", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					ContainerRules:     []overcommit.ContainerRule{{Name: "istio", ContainerName: "istio-(", Exclude: true}},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not a valid regex"))
+		})
+
+		It("Should fail validation for a container rule with an invalid ratio", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					ContainerRules: []overcommit.ContainerRule{
+						{Name: "jvm", Image: "*/java:*", Ratios: map[corev1.ResourceName]float64{corev1.ResourceMemory: 1.5}},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the memory ratio of the rule jvm"))
+		})
+
+		It("Should fail validation for init containers that are skipped and set ratios", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					InitContainers: &overcommit.InitContainersSpec{
+						Skip:   true,
+						Ratios: map[corev1.ResourceName]float64{corev1.ResourceCPU: 0.25},
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("initContainers can't set ratios"))
+		})
+
+	})
+
+	Context("ValidateUpdate", func() {
+		It("Should pass validation for a valid update", func() {
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			newOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.7,
+					MemoryOvercommit:   0.7,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for invalid memory overcommit in update", func() {
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			newOvercommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.7,
+					MemoryOvercommit:   -0.7, // Invalid value
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating  class"))
+		})
+
+		It("Should fail validation for invalid memory overcommit in update", func() {
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			newOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.7,
+					MemoryOvercommit:   -0.7, // Invalid value
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating test class"))
+		})
+	})
+
+	Context("ValidateDelete", func() {
+		It("Should pass validation for delete", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateDelete(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
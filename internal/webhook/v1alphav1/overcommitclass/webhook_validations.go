@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	plugincel "k8s.io/apiserver/pkg/admission/plugin/cel"
+	"k8s.io/apiserver/pkg/admission/plugin/webhook/matchconditions"
+	"k8s.io/apiserver/pkg/cel/environment"
+)
+
+func validateSpecOvercommit(class overcommit.OvercommitClass) error {
+	if class.Spec.CpuOvercommit <= 0 || class.Spec.CpuOvercommit > 1 {
+		return errors.New("Error: cpuOvercommit must be greater than 0 and equal or lower than 1, failed creating " + class.ObjectMeta.Name + " class ")
+	}
+	if class.Spec.MemoryOvercommit <= 0 || class.Spec.MemoryOvercommit > 1 {
+		return errors.New("Error: memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating " + class.ObjectMeta.Name + " class ")
+	}
+	if class.Spec.EphemeralStorageOvercommit < 0 || class.Spec.EphemeralStorageOvercommit > 1 {
+		return errors.New("Error: ephemeralStorageOvercommit must be greater than 0 and equal or lower than 1, failed creating " + class.ObjectMeta.Name + " class ")
+	}
+	return nil
+}
+
+func checkDecimals(class overcommit.OvercommitClass) error {
+	cpu := class.Spec.CpuOvercommit
+	memory := class.Spec.MemoryOvercommit
+	const precision = 10000 // 10^4
+	roundedCpu := math.Round(cpu*precision) / precision
+
+	if math.Abs(cpu-roundedCpu) > 1e-9 {
+		return errors.New("Error: the CPU value must have 4 decimals max")
+	}
+
+	roundedMemory := math.Round(memory*precision) / precision
+	if math.Abs(memory-roundedMemory) > 1e-9 {
+		return errors.New("Error: the memory value must have 4 decimals max")
+	}
+
+	ephemeralStorage := class.Spec.EphemeralStorageOvercommit
+	roundedEphemeralStorage := math.Round(ephemeralStorage*precision) / precision
+	if math.Abs(ephemeralStorage-roundedEphemeralStorage) > 1e-9 {
+		return errors.New("Error: the ephemeral-storage value must have 4 decimals max")
+	}
+	return nil
+}
+
+func isClassDefault(class overcommit.OvercommitClass, client client.Client) error {
+	// Create a context for the client
+	ctx := context.TODO()
+
+	// List all OvercommitClasses
+	var overcommitClassList overcommit.OvercommitClassList
+	err := client.List(ctx, &overcommitClassList)
+	if err != nil {
+		return fmt.Errorf("error listing OvercommitClasses: %w", err)
+	}
+
+	var existsDefault = false
+	if class.Spec.IsDefault {
+		for _, item := range overcommitClassList.Items {
+			if item.Spec.IsDefault {
+				existsDefault = true
+			}
+		}
+	}
+
+	if existsDefault {
+		return fmt.Errorf("error: only one OvercommitClass can be default, failed creating %s class", class.ObjectMeta.Name)
+	}
+
+	return nil
+}
+
+func checkIsRegexValid(regex string) error {
+	_, err := regexp.Compile(regex)
+	if err != nil {
+		return errors.New("Error: the regex is not valid")
+	}
+	return nil
+}
+
+func checkBaseline(class overcommit.OvercommitClass) error {
+	for name := range class.Spec.Baseline {
+		switch {
+		case name == corev1.ResourceCPU || name == corev1.ResourceMemory:
+		case name == corev1.ResourceEphemeralStorage && class.Spec.EphemeralStorageOvercommit != 0:
+		default:
+			return fmt.Errorf("error: baseline is set for %s, which has no overcommit ratio", name)
+		}
+	}
+	return nil
+}
+
+func checkRequestBounds(class overcommit.OvercommitClass) error {
+	for name, minRequest := range class.Spec.MinRequest {
+		if maxRequest, ok := class.Spec.MaxRequest[name]; ok && minRequest.Cmp(maxRequest) > 0 {
+			return fmt.Errorf("error: the minRequest of %s is greater than its maxRequest", name)
+		}
+	}
+	for name, maxRequest := range class.Spec.MaxRequest {
+		if maxRequest.Sign() <= 0 {
+			return fmt.Errorf("error: the maxRequest of %s must be greater than 0", name)
+		}
+	}
+	return nil
+}
+
+func checkSelectors(class overcommit.OvercommitClass) error {
+	if _, err := metav1.LabelSelectorAsSelector(class.Spec.NamespaceSelector); err != nil {
+		return fmt.Errorf("error: the namespaceSelector is not valid: %w", err)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(class.Spec.PodSelector); err != nil {
+		return fmt.Errorf("error: the podSelector is not valid: %w", err)
+	}
+	for _, name := range class.Spec.ExcludedNamespaceNames {
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return fmt.Errorf("error: %s in excludedNamespaceNames is not a valid namespace name: %s", name, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// matchConditionCompiler compiles match conditions with the CEL environment the API server
+// uses for the match conditions of admission webhooks.
+var matchConditionCompiler = sync.OnceValue(func() plugincel.Compiler {
+	return plugincel.NewCompiler(environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion(), true))
+})
+
+// checkMatchConditions rejects the match conditions whose names aren't valid or are taken by the
+// generated ones, and the expressions the API server wouldn't accept.
+func checkMatchConditions(class overcommit.OvercommitClass) error {
+	names := map[string]bool{
+		resources.ExcludeNamespacesMatchCondition: class.Spec.ExcludedNamespaces != "",
+		resources.OvercommitLabelMatchCondition:   true,
+	}
+	for i, condition := range class.Spec.MatchConditions {
+		if errs := validation.IsQualifiedName(condition.Name); len(errs) > 0 {
+			return fmt.Errorf("error: matchConditions[%d].name %q is not valid: %s", i, condition.Name, strings.Join(errs, ", "))
+		}
+		if names[condition.Name] {
+			return fmt.Errorf("error: matchConditions[%d].name %q is duplicated", i, condition.Name)
+		}
+		names[condition.Name] = true
+
+		result := matchConditionCompiler().CompileCELExpression(
+			&matchconditions.MatchCondition{Name: condition.Name, Expression: condition.Expression},
+			plugincel.OptionalVariableDeclarations{HasAuthorizer: true, StrictCost: true},
+			environment.NewExpressions,
+		)
+		if result.Error != nil {
+			return fmt.Errorf("error: matchConditions[%d] %q is not valid: %s", i, condition.Name, result.Error.Detail)
+		}
+	}
+	return nil
+}
+
+// checkContainerRules rejects the container rules whose patterns don't compile, whose ratios
+// aren't valid ratios of the class, or that exclude the containers and change their overcommit.
+func checkContainerRules(class overcommit.OvercommitClass) error {
+	for _, rule := range class.Spec.ContainerRules {
+		if _, err := regexp.Compile(rule.ContainerName); err != nil {
+			return fmt.Errorf("error: the containerName of the rule %s is not a valid regex: %w", rule.Name, err)
+		}
+		if rule.Exclude && (len(rule.Ratios) > 0 || len(rule.MinRequest) > 0) {
+			return fmt.Errorf("error: the rule %s excludes the containers, it can't set ratios or minRequest", rule.Name)
+		}
+		if err := checkRatios(rule.Ratios, "the rule "+rule.Name); err != nil {
+			return err
+		}
+		for name, minRequest := range rule.MinRequest {
+			if maxRequest, ok := class.Spec.MaxRequest[name]; ok && minRequest.Cmp(maxRequest) > 0 {
+				return fmt.Errorf("error: the minRequest of %s of the rule %s is greater than the maxRequest of the class", name, rule.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkInitContainers rejects the init container ratios that aren't valid ratios of the class,
+// or that are set along with skip.
+func checkInitContainers(class overcommit.OvercommitClass) error {
+	initContainers := class.Spec.InitContainers
+	if initContainers == nil {
+		return nil
+	}
+	if initContainers.Skip && len(initContainers.Ratios) > 0 {
+		return errors.New("error: the init containers are skipped, initContainers can't set ratios")
+	}
+	return checkRatios(initContainers.Ratios, "initContainers")
+}
+
+// checkRatios rejects the ratios that aren't between 0 and 1 with 4 decimals at most, or whose
+// resource can't be overcommitted: only CPU, memory and ephemeral-storage can, Kubernetes
+// requires the requests of hugepages and extended resources to be equal to their limits. where
+// tells where the ratios are set.
+func checkRatios(ratios map[corev1.ResourceName]float64, where string) error {
+	const precision = 10000 // 10^4
+	for name, ratio := range ratios {
+		switch {
+		case strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix):
+			return fmt.Errorf("error: hugepages can't be overcommitted, got %s in %s", name, where)
+		case name != corev1.ResourceCPU && name != corev1.ResourceMemory && name != corev1.ResourceEphemeralStorage:
+			return fmt.Errorf("error: %s can't be overcommitted, only cpu, memory and ephemeral-storage can, got it in %s", name, where)
+		case ratio <= 0 || ratio > 1:
+			return fmt.Errorf("error: the %s ratio of %s must be greater than 0 and equal or lower than 1", name, where)
+		case math.Abs(ratio-math.Round(ratio*precision)/precision) > 1e-9:
+			return fmt.Errorf("error: the %s ratio of %s must have 4 decimals max", name, where)
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 	corev1 "k8s.io/api/core/v1"
@@ -55,7 +56,7 @@ var _ webhook.CustomValidator = &PodCustomValidator{}
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Pod.
 func (v *PodCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 
-	label, err := utils.GetOvercommitLabel(ctx, v.Client)
+	labels, err := utils.GetOvercommitLabels(ctx, v.Client)
 	if err != nil {
 		return nil, err
 	}
@@ -66,10 +67,17 @@ func (v *PodCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Obj
 	}
 	podlog.Info("Validation for Pod upon creation", "name", pod.GetName())
 
-	value, exists := pod.Labels[label]
+	// During a label migration the new label takes precedence over the previous one
+	var value string
+	exists := false
+	for _, label := range labels {
+		if value, exists = pod.Labels[label]; exists {
+			break
+		}
+	}
 
 	if !exists {
-		return nil, errors.New("Pod without overcommit class label: " + label)
+		return nil, errors.New("Pod without overcommit class label: " + strings.Join(labels, ", "))
 	}
 
 	unstructuredObj := unstructured.Unstructured{}
@@ -45,7 +45,6 @@ func TestWebhookSuite(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
-	os.Setenv("OVERCOMMIT_CLASS_NAME", "default-overcommitclass")
 	os.Setenv("LABEL_OVERCOMMIT_CLASS", "inditex.com/overcommit-class")
 	log.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
 
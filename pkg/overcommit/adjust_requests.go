@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	mebibyte = 1 << 20
+	gibibyte = 1 << 30
+)
+
+// request calculates the request of a resource from its baseline: it applies the ratio,
+// rounds the result and clamps it between minRequest and maxRequest. The request is
+// never zero and never above the limit, the clamps applied are returned.
+func request(name corev1.ResourceName, base resource.Quantity, limit *resource.Quantity, ratio float64, spec overcommit.OvercommitClassSpec) (resource.Quantity, []Clamp) {
+	var clamps []Clamp
+	clamp := func(reason ClampReason, from, to int64) int64 {
+		clamps = append(clamps, Clamp{Resource: name, Reason: reason, Calculated: toQuantity(name, from, base.Format)})
+		return to
+	}
+
+	value := int64(float64(toValue(name, base)) * ratio)
+	if step := roundingStep(name, spec.Rounding); step > 1 {
+		rounded := (value + step/2) / step * step
+		if rounded == 0 && value > 0 {
+			rounded = step
+		}
+		value = rounded
+	}
+
+	if minRequest, ok := spec.MinRequest[name]; ok && value < toValue(name, minRequest) {
+		value = clamp(ClampMinRequest, value, toValue(name, minRequest))
+	}
+	if maxRequest, ok := spec.MaxRequest[name]; ok && value > toValue(name, maxRequest) {
+		value = clamp(ClampMaxRequest, value, toValue(name, maxRequest))
+	}
+	if limit != nil && value > toValue(name, *limit) {
+		value = clamp(ClampLimit, value, toValue(name, *limit))
+	}
+	if value <= 0 && (limit == nil || toValue(name, *limit) > 0) {
+		value = clamp(ClampNonZero, value, 1)
+	}
+
+	return toQuantity(name, value, base.Format), clamps
+}
+
+// roundingStep returns the multiple the request of the resource is rounded to, in the
+// unit of toValue.
+func roundingStep(name corev1.ResourceName, rounding *overcommit.RoundingSpec) int64 {
+	if rounding == nil {
+		return 1
+	}
+	switch {
+	case name == corev1.ResourceCPU && rounding.CpuMillicores > 0:
+		return rounding.CpuMillicores
+	case name == corev1.ResourceMemory && rounding.Memory == overcommit.MemoryRoundingMi:
+		return mebibyte
+	case name == corev1.ResourceMemory && rounding.Memory == overcommit.MemoryRoundingGi:
+		return gibibyte
+	default:
+		return 1
+	}
+}
+
+// toValue returns the quantity in millicores for CPU, bytes for memory and
+// ephemeral-storage, and thousandths for any other resource.
+func toValue(name corev1.ResourceName, quantity resource.Quantity) int64 {
+	switch name {
+	case corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+		return quantity.Value()
+	default:
+		return quantity.MilliValue()
+	}
+}
+
+// toQuantity is the inverse of toValue.
+func toQuantity(name corev1.ResourceName, value int64, format resource.Format) resource.Quantity {
+	switch name {
+	case corev1.ResourceCPU:
+		return *resource.NewMilliQuantity(value, resource.DecimalSI)
+	case corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+		return *resource.NewQuantity(value, resource.BinarySI)
+	default:
+		return *resource.NewMilliQuantity(value, format)
+	}
+}
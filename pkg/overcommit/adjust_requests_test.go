@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("request", func() {
+	var spec overcommit.OvercommitClassSpec
+
+	BeforeEach(func() {
+		spec = overcommit.OvercommitClassSpec{CpuOvercommit: 0.1, MemoryOvercommit: 0.3}
+	})
+
+	It("should never return a zero request", func() {
+		limit := resource.MustParse("5m")
+		quantity, clamps := request(corev1.ResourceCPU, limit, &limit, spec.CpuOvercommit, spec)
+
+		Expect(quantity.MilliValue()).To(Equal(int64(1)))
+		Expect(clamps).To(ConsistOf(Clamp{Resource: corev1.ResourceCPU, Reason: ClampNonZero, Calculated: *resource.NewMilliQuantity(0, resource.DecimalSI)}))
+	})
+
+	It("should round the CPU to the millicores of the class", func() {
+		spec.Rounding = &overcommit.RoundingSpec{CpuMillicores: 50}
+		limit := resource.MustParse("1300m")
+		quantity, clamps := request(corev1.ResourceCPU, limit, &limit, spec.CpuOvercommit, spec)
+
+		Expect(quantity.MilliValue()).To(Equal(int64(150)))
+		Expect(clamps).To(BeEmpty())
+	})
+
+	It("should round the memory to Mi and Gi boundaries without reaching zero", func() {
+		spec.Rounding = &overcommit.RoundingSpec{Memory: overcommit.MemoryRoundingMi}
+		limit := resource.MustParse("1000Mi")
+		quantity, _ := request(corev1.ResourceMemory, limit, &limit, spec.MemoryOvercommit, spec)
+		Expect(quantity.Value()).To(Equal(int64(300 * mebibyte)))
+
+		spec.Rounding.Memory = overcommit.MemoryRoundingGi
+		limit = resource.MustParse("4Gi")
+		quantity, _ = request(corev1.ResourceMemory, limit, &limit, spec.MemoryOvercommit, spec)
+		Expect(quantity.Value()).To(Equal(int64(gibibyte)))
+	})
+
+	It("should not round above the limit", func() {
+		spec.Rounding = &overcommit.RoundingSpec{Memory: overcommit.MemoryRoundingGi}
+		limit := resource.MustParse("512Mi")
+		quantity, clamps := request(corev1.ResourceMemory, limit, &limit, spec.MemoryOvercommit, spec)
+
+		Expect(quantity.Value()).To(Equal(int64(512 * mebibyte)))
+		Expect(clamps).To(HaveLen(1))
+		Expect(clamps[0].Reason).To(Equal(ClampLimit))
+		Expect(clamps[0].Calculated.Value()).To(Equal(int64(gibibyte)))
+	})
+
+	It("should clamp between minRequest and maxRequest", func() {
+		spec.MinRequest = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")}
+		spec.MaxRequest = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+
+		limit := resource.MustParse("1")
+		quantity, clamps := request(corev1.ResourceCPU, limit, &limit, spec.CpuOvercommit, spec)
+		Expect(quantity.MilliValue()).To(Equal(int64(200)))
+		Expect(clamps[0].Reason).To(Equal(ClampMinRequest))
+
+		limit = resource.MustParse("10Gi")
+		quantity, clamps = request(corev1.ResourceMemory, limit, &limit, spec.MemoryOvercommit, spec)
+		Expect(quantity.Value()).To(Equal(int64(gibibyte)))
+		Expect(clamps[0].Reason).To(Equal(ClampMaxRequest))
+	})
+
+	It("should keep the request at or below the limit over the minRequest", func() {
+		spec.MinRequest = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")}
+		limit := resource.MustParse("100m")
+		quantity, clamps := request(corev1.ResourceCPU, limit, &limit, spec.CpuOvercommit, spec)
+
+		Expect(quantity.MilliValue()).To(Equal(int64(100)))
+		Expect(clamps).To(HaveLen(2))
+		Expect(clamps[1].Reason).To(Equal(ClampLimit))
+	})
+})
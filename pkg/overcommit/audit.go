@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AuditClassAnnotation is set on the pods audited by a class in Audit mode, with the
+	// name of the class.
+	AuditClassAnnotation = "overcommit.inditex.dev/audit-class"
+	// AuditRequestsAnnotation is set on the pods audited by a class in Audit mode, with the
+	// requests the class would set, per container, as JSON.
+	AuditRequestsAnnotation = "overcommit.inditex.dev/audit-requests"
+)
+
+// Audit records the requests calculated in the decision in the annotations of the pod,
+// without changing its containers.
+func Audit(pod *corev1.Pod, decision Decision) error {
+	requests := make(map[string]corev1.ResourceList, len(decision.Containers))
+	for _, container := range decision.Containers {
+		requests[container.Name] = container.After
+	}
+	raw, err := json.Marshal(requests)
+	if err != nil {
+		return NewResolutionError(FailureAudit, fmt.Errorf("error encoding the audited requests: %w", err))
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[AuditClassAnnotation] = decision.ClassName()
+	pod.Annotations[AuditRequestsAnnotation] = string(raw)
+	return nil
+}
+
+// Savings returns, per resource, how much the effective requests of the pod, the ones the
+// scheduler sees, are lowered by the decision. Init containers only count when they request more
+// than the containers and native sidecars of the pod.
+func Savings(decision Decision) corev1.ResourceList {
+	savings := corev1.ResourceList{}
+	for name, before := range decision.EffectiveBefore {
+		after, ok := decision.EffectiveAfter[name]
+		if !ok {
+			continue
+		}
+		saved := before.DeepCopy()
+		saved.Sub(after)
+		if saved.Sign() > 0 {
+			savings[name] = saved
+		}
+	}
+	return savings
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("Audit", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		testClasses[0].Spec.Mode = overcommit.ModeAudit
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				Labels: map[string]string{
+					"inditex.com/overcommit-class": "test-class",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "test-container",
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("1"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		testClasses[0].Spec.Mode = ""
+	})
+
+	It("should take the mode of the class, Enforce by default", func() {
+		Expect(Decide(pod, nil, testClasses, testOvercommitConfig).Audited()).To(BeTrue())
+
+		testClasses[0].Spec.Mode = ""
+		decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+		Expect(decision.Mode).To(Equal(overcommit.ModeEnforce))
+		Expect(decision.Audited()).To(BeFalse())
+	})
+
+	It("should annotate the pod with the would-be requests without changing them", func() {
+		decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+		Expect(Audit(pod, decision)).To(Succeed())
+
+		Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
+		Expect(pod.Annotations).To(HaveKeyWithValue(AuditClassAnnotation, "test-class"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(AuditRequestsAnnotation, `{"test-container":{"cpu":"500m","memory":"512Mi"}}`))
+	})
+
+	It("should sum the savings of the containers", func() {
+		second := *pod.Spec.Containers[0].DeepCopy()
+		second.Name = "second-container"
+		second.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("800m")}
+		pod.Spec.Containers = append(pod.Spec.Containers, second)
+		pod.Spec.InitContainers = []corev1.Container{*pod.Spec.Containers[0].DeepCopy()}
+		pod.Spec.InitContainers[0].Name = "test-init-container"
+		decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+		savings := Savings(decision)
+		Expect(savings.Cpu().MilliValue()).To(Equal(int64(800)))
+		Expect(savings.Memory().Value()).To(Equal(int64(1 << 30)))
+	})
+
+	It("should not count the requests raised by the decision", func() {
+		pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+		decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+		savings := Savings(decision)
+		Expect(savings).NotTo(HaveKey(corev1.ResourceCPU))
+		Expect(savings.Memory().Value()).To(Equal(int64(512 << 20)))
+	})
+})
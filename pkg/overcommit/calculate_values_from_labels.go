@@ -6,107 +6,50 @@
 package overcommit
 
 import (
-	"context"
-	"fmt"
-
-	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
-	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 	corev1 "k8s.io/api/core/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/yaml"
 )
 
-// GetLabelValue gets the value of a label in the pod or in the namespace of the pod
-func getNamespaceOvercommit(ctx context.Context, pod *corev1.Pod, client client.Client, label, ownerName, ownerKind string) (float64, float64) {
-	// Get the namespace of the pod
-	namespaceName := pod.ObjectMeta.Namespace
-	var ns corev1.Namespace
-	namespace, err := getNamespaceYAML(ctx, namespaceName, client)
-	if err != nil {
-		podlog.Error(err, "Error getting the namespace yaml", "namespace", namespaceName)
-	}
-	err = yaml.Unmarshal([]byte(namespace), &ns)
-	if err != nil {
-		podlog.Error(err, "Error unmarshaling the namespace", "namespace", namespaceName)
+// resolveClass chooses the OvercommitClass of a pod: the class named in the pod labels,
+// then the class named in the namespace labels, and finally the default class. The labels are
+// looked up in order, so a label migration can honor the previous label after the new one.
+// A label naming a class that doesn't exist falls through to the next step.
+func resolveClass(pod *corev1.Pod, namespace *corev1.Namespace, classes []overcommit.OvercommitClass, labels ...string) (*overcommit.OvercommitClass, ResolutionSource) {
+	if class := classFromLabels(pod.Labels, classes, labels); class != nil {
+		return class, SourcePodLabel
 	}
 
-	// Check if the overcommit class label is in the namespace
-	if val, ok := ns.Labels[label]; ok {
-		podlog.Info("Namespace class found", "class", val)
-		overcommitClass, err := utils.GetOvercommitClassSpec(ctx, val, client)
-		if err != nil {
-			podlog.Error(err, "Error getting the overcommit class", "overcommitClassLabel", val)
+	if namespace != nil {
+		if class := classFromLabels(namespace.Labels, classes, labels); class != nil {
+			return class, SourceNamespaceLabel
 		}
-		metrics.K8sOvercommitPodMutated.WithLabelValues(val, ownerKind, ownerName, pod.Namespace).Inc()
-		return overcommitClass.CpuOvercommit, overcommitClass.MemoryOvercommit
-	} else {
-		podlog.Info("Overcommit class not found in the namespace, using the default", "namespace", ns.Name)
-		defaultClass, error := utils.GetDefaultSpec(client)
-		if error != nil {
-			podlog.Error(error, "Error getting the default overcommit class")
-		}
-		metrics.K8sOvercommitPodMutated.WithLabelValues("default", ownerKind, ownerName, pod.Namespace).Inc()
-		return defaultClass.CpuOvercommit, defaultClass.MemoryOvercommit
-	}
-}
-
-// getNamespaceYAML gets the YAML of a namespace using the ServiceAccount token
-func getNamespaceYAML(ctx context.Context, namespaceName string, k8sClient client.Client) (string, error) {
-	// Create a variable to store the Namespace object
-	var namespace corev1.Namespace
-
-	// Get the Namespace object from the API server
-	err := k8sClient.Get(ctx, client.ObjectKey{
-		Name: namespaceName,
-	}, &namespace)
-	if err != nil {
-		return "", fmt.Errorf("error getting the namespace: %v", err)
 	}
 
-	// Convert the Namespace object to YAML
-	nsYAML, err := yaml.Marshal(namespace)
-	if err != nil {
-		return "", fmt.Errorf("error converting the namespace to YAML: %v", err)
+	for i := range classes {
+		if classes[i].Spec.IsDefault {
+			return &classes[i], SourceDefault
+		}
 	}
-
-	return string(nsYAML), nil
+	return nil, SourceNone
 }
 
-func checkOvercommitType(ctx context.Context, pod corev1.Pod, client client.Client) (float64, float64) {
-	var cpuValue float64
-	var memoryValue float64
-	ownerName, ownerKind, err := utils.GetPodOwner(ctx, client, &pod)
-	if err != nil {
-		podlog.Error(err, "Error getting the pod owner")
+// classFromLabels returns the existing class named by the first of labels set in objectLabels.
+func classFromLabels(objectLabels map[string]string, classes []overcommit.OvercommitClass, labels []string) *overcommit.OvercommitClass {
+	for _, label := range labels {
+		if value, exists := objectLabels[label]; exists {
+			if class := findClass(classes, value); class != nil {
+				return class
+			}
+		}
 	}
+	return nil
+}
 
-	label, err := utils.GetOvercommitLabel(ctx, client)
-	if err != nil {
-		podlog.Error(err, "Error getting the overcommit label")
-	}
-	//  Check if the pod has the overcommit class label
-	value, exists := pod.Labels[label]
-	podlog.Info(
-		"Checking if pod has overcommit class label",
-		"overcommitClassLabel", value,
-		"exists", exists,
-	)
-	if exists {
-		// Overcommit class found in pod
-		overcommitClass, err := utils.GetOvercommitClassSpec(ctx, value, client)
-		if err != nil {
-			podlog.Error(err, "Error getting the overcommit class", "overcommitClassLabel", value)
-			// Overcommit class not found or some error
-			cpuValue, memoryValue = getNamespaceOvercommit(ctx, &pod, client, label, ownerName, ownerKind)
-		} else {
-			cpuValue, memoryValue = overcommitClass.CpuOvercommit, overcommitClass.MemoryOvercommit
+func findClass(classes []overcommit.OvercommitClass, name string) *overcommit.OvercommitClass {
+	for i := range classes {
+		if classes[i].Name == name {
+			return &classes[i]
 		}
-		metrics.K8sOvercommitPodMutated.WithLabelValues(value, ownerKind, ownerName, pod.Namespace).Inc()
-	} else {
-		// Overcommit class not found, checking the overcommit labels
-		podlog.Info("Overcommit class label not found in pod, checking the namespace")
-		cpuValue, memoryValue = getNamespaceOvercommit(ctx, &pod, client, label, ownerName, ownerKind)
-
 	}
-	return cpuValue, memoryValue
+	return nil
 }
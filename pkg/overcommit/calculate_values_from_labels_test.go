@@ -6,37 +6,90 @@
 package overcommit
 
 import (
-	"context"
-
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 )
 
-var _ = Describe("Overcommit Functions", func() {
+var _ = Describe("resolveClass", func() {
+	var (
+		pod       *corev1.Pod
+		namespace *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "test-namespace",
+				Labels: map[string]string{
+					"inditex.com/overcommit-class": "test-class",
+				},
+			},
+		}
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-namespace",
+				Labels: map[string]string{
+					"inditex.com/overcommit-class": "namespace-class",
+				},
+			},
+		}
+	})
 
-	Describe("getNamespaceOvercommit", func() {
-		It("should return the correct overcommit values from the namespace", func() {
-			cpuOvercommit, memoryOvercommit := getNamespaceOvercommit(context.TODO(), testPod, k8sClient, "inditex.com/overcommit-class", "ownerName", "ownerKind")
-			Expect(cpuOvercommit).To(Equal(0.5))
-			Expect(memoryOvercommit).To(Equal(0.5))
-		})
+	It("should use the class of the pod label first", func() {
+		class, source := resolveClass(pod, namespace, testClasses, testOvercommitConfig.OvercommitLabel)
+		Expect(class.Name).To(Equal("test-class"))
+		Expect(source).To(Equal(SourcePodLabel))
 	})
 
-	Describe("checkOvercommitType", func() {
-		It("should return the correct overcommit values from the pod", func() {
-			cpuOvercommit, memoryOvercommit := checkOvercommitType(context.TODO(), *testPod, k8sClient)
-			Expect(cpuOvercommit).To(Equal(0.5))
-			Expect(memoryOvercommit).To(Equal(0.5))
-		})
+	It("should fallback to the namespace label if the pod label is missing", func() {
+		delete(pod.Labels, "inditex.com/overcommit-class")
+		class, source := resolveClass(pod, namespace, testClasses, testOvercommitConfig.OvercommitLabel)
+		Expect(class.Name).To(Equal("namespace-class"))
+		Expect(source).To(Equal(SourceNamespaceLabel))
+	})
+
+	It("should fallback to the namespace label if the pod class doesn't exist", func() {
+		pod.Labels["inditex.com/overcommit-class"] = "unknown"
+		class, source := resolveClass(pod, namespace, testClasses, testOvercommitConfig.OvercommitLabel)
+		Expect(class.Name).To(Equal("namespace-class"))
+		Expect(source).To(Equal(SourceNamespaceLabel))
+	})
+
+	It("should use the default class if no label is found", func() {
+		delete(pod.Labels, "inditex.com/overcommit-class")
+		class, source := resolveClass(pod, nil, testClasses, testOvercommitConfig.OvercommitLabel)
+		Expect(class.Name).To(Equal("default-class"))
+		Expect(source).To(Equal(SourceDefault))
+	})
+
+	It("should resolve nothing without a default class", func() {
+		delete(pod.Labels, "inditex.com/overcommit-class")
+		class, source := resolveClass(pod, nil, testClasses[:2], testOvercommitConfig.OvercommitLabel)
+		Expect(class).To(BeNil())
+		Expect(source).To(Equal(SourceNone))
+	})
+
+	It("should not fail without classes", func() {
+		class, source := resolveClass(pod, namespace, []overcommit.OvercommitClass{}, testOvercommitConfig.OvercommitLabel)
+		Expect(class).To(BeNil())
+		Expect(source).To(Equal(SourceNone))
+	})
 
-		It("should fallback to namespace overcommit values if pod label is missing", func() {
-			delete(testPod.Labels, "LABEL_OVERCOMMIT_CLASS")
-			err := k8sClient.Update(context.TODO(), testPod)
-			Expect(err).NotTo(HaveOccurred())
+	It("should honor the previous label after the new one during a migration", func() {
+		delete(pod.Labels, "inditex.com/overcommit-class")
+		pod.Labels["inditex.dev/overcommit-class"] = "test-class"
+		class, source := resolveClass(pod, namespace, testClasses, "inditex.dev/overcommit-class", "inditex.com/overcommit-class")
+		Expect(class.Name).To(Equal("test-class"))
+		Expect(source).To(Equal(SourcePodLabel))
 
-			cpuOvercommit, memoryOvercommit := checkOvercommitType(context.TODO(), *testPod, k8sClient)
-			Expect(cpuOvercommit).To(Equal(0.5))
-			Expect(memoryOvercommit).To(Equal(0.5))
-		})
+		delete(pod.Labels, "inditex.dev/overcommit-class")
+		class, source = resolveClass(pod, namespace, testClasses, "inditex.dev/overcommit-class", "inditex.com/overcommit-class")
+		Expect(class.Name).To(Equal("namespace-class"))
+		Expect(source).To(Equal(SourceNamespaceLabel))
 	})
 })
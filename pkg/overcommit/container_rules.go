@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"maps"
+	"regexp"
+	"strings"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// containerRule is a ContainerRule of the class with its patterns compiled. A pattern that
+// doesn't compile never matches.
+type containerRule struct {
+	overcommit.ContainerRule
+	name  *regexp.Regexp
+	image *regexp.Regexp
+}
+
+// containerRules compiles the container rules of the class, in order.
+func containerRules(spec overcommit.OvercommitClassSpec) []containerRule {
+	rules := make([]containerRule, 0, len(spec.ContainerRules))
+	for _, rule := range spec.ContainerRules {
+		compiled := containerRule{ContainerRule: rule}
+		if rule.ContainerName != "" {
+			compiled.name = compile("^(?:" + rule.ContainerName + ")$")
+		}
+		if rule.Image != "" {
+			compiled.image = compile(globToRegexp(rule.Image))
+		}
+		rules = append(rules, compiled)
+	}
+	return rules
+}
+
+// compile returns the regular expression, or one that never matches when it is not valid.
+func compile(expr string) *regexp.Regexp {
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return regexp.MustCompile(`[^\s\S]`)
+	}
+	return compiled
+}
+
+// globToRegexp returns the anchored regular expression of the image glob of a ContainerRule:
+// * matches any sequence of characters and ? any single character.
+func globToRegexp(glob string) string {
+	quoted := regexp.QuoteMeta(glob)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return "^" + quoted + "$"
+}
+
+// matches tells whether the rule applies to the container: its name and its image match the
+// patterns the rule sets.
+func (r containerRule) matches(container corev1.Container) bool {
+	if r.name != nil && !r.name.MatchString(container.Name) {
+		return false
+	}
+	return r.image == nil || r.image.MatchString(container.Image)
+}
+
+// matchRule returns the first rule that applies to the container, nil when there is none.
+func matchRule(rules []containerRule, container corev1.Container) *containerRule {
+	for i := range rules {
+		if rules[i].matches(container) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// withRule returns the spec of the class with the ratios and the minRequest of the rule over
+// its own.
+func withRule(spec overcommit.OvercommitClassSpec, rule *containerRule) overcommit.OvercommitClassSpec {
+	if rule == nil {
+		return spec
+	}
+	spec = withRatios(spec, rule.Ratios)
+	if len(rule.MinRequest) > 0 {
+		spec.MinRequest = spec.MinRequest.DeepCopy()
+		if spec.MinRequest == nil {
+			spec.MinRequest = corev1.ResourceList{}
+		}
+		maps.Copy(spec.MinRequest, rule.MinRequest)
+	}
+	return spec
+}
+
+// withRatios returns the spec of the class with the ratios over its own. The ratios of the
+// resources that can't be overcommitted are ignored.
+func withRatios(spec overcommit.OvercommitClassSpec, ratios map[corev1.ResourceName]float64) overcommit.OvercommitClassSpec {
+	for name, ratio := range ratios {
+		switch name {
+		case corev1.ResourceCPU:
+			spec.CpuOvercommit = ratio
+		case corev1.ResourceMemory:
+			spec.MemoryOvercommit = ratio
+		case corev1.ResourceEphemeralStorage:
+			spec.EphemeralStorageOvercommit = ratio
+		}
+	}
+	return spec
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("Container rules", func() {
+	var (
+		pod     *corev1.Pod
+		classes []overcommit.OvercommitClass
+	)
+
+	container := func(name, image string) corev1.Container {
+		return corev1.Container{
+			Name:  name,
+			Image: image,
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		classes = []overcommit.OvercommitClass{*testClasses[0].DeepCopy()}
+		classes[0].Spec.ContainerRules = []overcommit.ContainerRule{
+			{Name: "logs", ContainerName: "fluent-bit|filebeat", Exclude: true},
+			{Name: "istio", ContainerName: "istio-.*", Ratios: map[corev1.ResourceName]float64{corev1.ResourceCPU: 0.1}},
+			{Name: "jvm", Image: "*/java:*", MinRequest: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("768Mi")}},
+			{Name: "all", Ratios: map[corev1.ResourceName]float64{corev1.ResourceCPU: 1, corev1.ResourceMemory: 1}},
+		}
+		sidecar := container("istio-proxy", "docker.io/istio/proxyv2:1.20")
+		sidecar.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				Labels:    map[string]string{"inditex.com/overcommit-class": "test-class"},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{sidecar},
+				Containers: []corev1.Container{
+					container("app", "registry.example.com/teams/java:21"),
+					container("fluent-bit", "fluent/fluent-bit:3.0"),
+					container("other", "busybox"),
+				},
+			},
+		}
+	})
+
+	It("should apply the first rule that matches each container", func() {
+		decision := Decide(pod, nil, classes, testOvercommitConfig)
+
+		Expect(decision.Containers).To(HaveLen(2))
+		Expect(decision.Containers[0].Name).To(Equal("app"))
+		Expect(decision.Containers[0].Rule).To(Equal("jvm"))
+		Expect(decision.Containers[0].After.Cpu().MilliValue()).To(Equal(int64(500)))
+		Expect(decision.Containers[0].After.Memory().String()).To(Equal("768Mi"))
+		Expect(decision.Containers[1].Name).To(Equal("istio-proxy"))
+		Expect(decision.Containers[1].InitContainer).To(BeTrue())
+		Expect(decision.Containers[1].Rule).To(Equal("istio"))
+		Expect(decision.Containers[1].After.Cpu().MilliValue()).To(Equal(int64(100)))
+		Expect(decision.Containers[1].After.Memory().String()).To(Equal("512Mi"))
+		Expect(decision.Skipped).To(ConsistOf(
+			SkippedContainer{Name: "fluent-bit", Reason: ReasonExcludedByRule, Rule: "logs"},
+			SkippedContainer{Name: "other", Reason: ReasonOvercommitOne, Rule: "all"},
+		))
+	})
+
+	It("should require the name and the image when a rule sets both", func() {
+		classes[0].Spec.ContainerRules = []overcommit.ContainerRule{
+			{Name: "istio", ContainerName: "istio-proxy", Image: "*/envoy:*", Exclude: true},
+		}
+		decision := Decide(pod, nil, classes, testOvercommitConfig)
+
+		Expect(decision.Skipped).To(BeEmpty())
+		Expect(decision.Containers).To(HaveLen(4))
+	})
+
+	It("should ignore the rules whose container name isn't a valid regex", func() {
+		classes[0].Spec.ContainerRules = []overcommit.ContainerRule{{Name: "invalid", ContainerName: "(", Exclude: true}}
+		decision := Decide(pod, nil, classes, testOvercommitConfig)
+
+		Expect(decision.Skipped).To(BeEmpty())
+	})
+
+	It("should not change the class", func() {
+		Decide(pod, nil, classes, testOvercommitConfig)
+
+		Expect(classes[0].Spec.CpuOvercommit).To(Equal(0.5))
+		Expect(classes[0].Spec.MinRequest).To(BeNil())
+	})
+
+	It("should match the image globs", func() {
+		Expect(globToRegexp("*/java:*")).To(Equal(`^.*/java:.*$`))
+		Expect(compile(globToRegexp("docker.io/istio/proxyv2:1.2?")).MatchString("docker.io/istio/proxyv2:1.20")).To(BeTrue())
+		Expect(compile(globToRegexp("istio/proxyv2:*")).MatchString("docker.io/istio/proxyv2:1.20")).To(BeFalse())
+	})
+})
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResolutionSource tells where the OvercommitClass of a pod was taken from.
+type ResolutionSource string
+
+const (
+	// SourcePodLabel means the class is the value of the overcommit label of the pod.
+	SourcePodLabel ResolutionSource = "PodLabel"
+	// SourceNamespaceLabel means the class is the value of the overcommit label of the namespace.
+	SourceNamespaceLabel ResolutionSource = "NamespaceLabel"
+	// SourceDefault means the class is the OvercommitClass marked as default.
+	SourceDefault ResolutionSource = "Default"
+	// SourceNone means no OvercommitClass could be resolved for the pod.
+	SourceNone ResolutionSource = "None"
+)
+
+// BaselineSource tells which value of a container resource the overcommit ratio was applied to.
+type BaselineSource string
+
+const (
+	// BaselineLimits means the ratio was applied to the limit of the resource.
+	BaselineLimits BaselineSource = "limits"
+	// BaselineRequests means the ratio was applied to the request of the resource.
+	BaselineRequests BaselineSource = "requests"
+)
+
+// ClampReason tells why a calculated request was changed after the ratio and the rounding.
+type ClampReason string
+
+const (
+	// ClampMinRequest means the request was raised to the minRequest of the class.
+	ClampMinRequest ClampReason = "MinRequest"
+	// ClampMaxRequest means the request was lowered to the maxRequest of the class.
+	ClampMaxRequest ClampReason = "MaxRequest"
+	// ClampLimit means the request was lowered to the limit of the container.
+	ClampLimit ClampReason = "Limit"
+	// ClampNonZero means the request was zero and was raised to the smallest unit.
+	ClampNonZero ClampReason = "NonZero"
+	// ClampExistingRequest means the OnlyLower policy kept the lower request the container already set.
+	ClampExistingRequest ClampReason = "ExistingRequest"
+)
+
+// Clamp records a request that was changed to respect the bounds of the class or the container.
+type Clamp struct {
+	Resource corev1.ResourceName
+	Reason   ClampReason
+	// Calculated is the request before the clamp.
+	Calculated resource.Quantity
+}
+
+// Reasons why a container is left untouched.
+const (
+	ReasonNoLimits         = "no limits"
+	ReasonNoBaseline       = "no baseline"
+	ReasonExplicitRequests = "explicit requests"
+	ReasonOvercommitOne    = "overcommit values = 1"
+	ReasonExcludedByRule   = "excluded by rule"
+	ReasonInitContainer    = "run-to-completion init container"
+)
+
+// ContainerDecision holds the requests of a container before and after the overcommit. Sidecar
+// tells whether the init container is a native sidecar.
+type ContainerDecision struct {
+	Name          string
+	InitContainer bool
+	Sidecar       bool
+	Before        corev1.ResourceList
+	After         corev1.ResourceList
+	// Baselines records, per overcommitted resource, the value the ratio was applied to.
+	Baselines map[corev1.ResourceName]BaselineSource
+	// Clamps lists, in order, the bounds applied to the calculated requests.
+	Clamps []Clamp
+	// Rule is the name of the container rule of the class that applied, empty when none did.
+	Rule string
+}
+
+// SkippedContainer is a container the overcommit is not applied to.
+type SkippedContainer struct {
+	Name          string
+	InitContainer bool
+	Sidecar       bool
+	Reason        string
+	// Rule is the name of the container rule of the class that applied, empty when none did.
+	Rule string
+}
+
+// Decision is the result of Decide. It describes the overcommit of a pod without applying it.
+type Decision struct {
+	// Class is the chosen OvercommitClass, nil when Source is SourceNone.
+	Class  *overcommit.OvercommitClass
+	Source ResolutionSource
+	// RequestPolicy is the policy applied to the existing requests, from the class or the pod.
+	RequestPolicy overcommit.RequestPolicy
+	// Mode tells whether the decision is applied to the pod or only audited.
+	Mode       overcommit.Mode
+	Containers []ContainerDecision
+	Skipped    []SkippedContainer
+	// EffectiveBefore and EffectiveAfter are the effective requests of the pod, the ones the
+	// scheduler sees, before and after the decision. See EffectiveRequests.
+	EffectiveBefore corev1.ResourceList
+	EffectiveAfter  corev1.ResourceList
+}
+
+// ClassName returns the name of the chosen OvercommitClass, or an empty string if there is none.
+func (d Decision) ClassName() string {
+	if d.Class == nil {
+		return ""
+	}
+	return d.Class.Name
+}
+
+// Audited reports whether the class is in Audit mode: the decision is recorded on the pod
+// but its requests are left untouched.
+func (d Decision) Audited() bool {
+	return d.Mode == overcommit.ModeAudit
+}
+
+// Mutated reports whether applying the decision changes the requests of any container.
+func (d Decision) Mutated() bool {
+	return len(d.Containers) > 0
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Sidecar tells whether the init container is a native sidecar: with restartPolicy Always it
+// keeps running next to the containers of the pod.
+func Sidecar(container corev1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// EffectiveRequests returns the requests of the pod the scheduler sees, as Kubernetes computes
+// them: the highest of the sum of its containers and native sidecars, and of each init container
+// plus the sidecars started before it, plus the overhead of the pod. A container without a
+// request is scheduled with its limit.
+func EffectiveRequests(pod *corev1.Pod) corev1.ResourceList {
+	running := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(running, containerRequests(container))
+	}
+
+	initialization := corev1.ResourceList{}
+	sidecars := corev1.ResourceList{}
+	for _, container := range pod.Spec.InitContainers {
+		requests := containerRequests(container)
+		if Sidecar(container) {
+			addResources(running, requests)
+			addResources(sidecars, requests)
+			maxResources(initialization, sidecars)
+			continue
+		}
+		addResources(requests, sidecars)
+		maxResources(initialization, requests)
+	}
+
+	maxResources(running, initialization)
+	addResources(running, pod.Spec.Overhead)
+	return running
+}
+
+// containerRequests returns the requests of the container, with its limits for the resources
+// it doesn't request.
+func containerRequests(container corev1.Container) corev1.ResourceList {
+	requests := container.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	for name, limit := range container.Resources.Limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = limit.DeepCopy()
+		}
+	}
+	return requests
+}
+
+// addResources adds the quantities of add to list.
+func addResources(list, add corev1.ResourceList) {
+	for name, quantity := range add {
+		total, ok := list[name]
+		if !ok {
+			list[name] = quantity.DeepCopy()
+			continue
+		}
+		total.Add(quantity)
+		list[name] = total
+	}
+}
+
+// maxResources sets in list, per resource, the highest of its quantity and the one of other.
+func maxResources(list, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := list[name]; !ok || quantity.Cmp(current) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("Effective requests", func() {
+	var (
+		pod     *corev1.Pod
+		classes []overcommit.OvercommitClass
+	)
+
+	container := func(name, cpu, memory string) corev1.Container {
+		return corev1.Container{
+			Name: name,
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		classes = []overcommit.OvercommitClass{*testClasses[0].DeepCopy()}
+		sidecar := container("proxy", "500m", "256Mi")
+		sidecar.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				Labels:    map[string]string{"inditex.com/overcommit-class": "test-class"},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					container("setup", "2", "512Mi"),
+					sidecar,
+					container("migrate", "1", "1Gi"),
+				},
+				Containers: []corev1.Container{container("app", "1", "1Gi")},
+			},
+		}
+	})
+
+	It("should count the sidecars with the containers and the init containers one at a time", func() {
+		requests := EffectiveRequests(pod)
+
+		// cpu: max(setup 2, migrate 1 + proxy 500m, app 1 + proxy 500m)
+		Expect(requests.Cpu().MilliValue()).To(Equal(int64(2000)))
+		// memory: max(setup 512Mi, migrate 1Gi + proxy 256Mi, app 1Gi + proxy 256Mi)
+		Expect(requests.Memory().Value()).To(Equal(int64(1280 << 20)))
+	})
+
+	It("should add the overhead of the pod", func() {
+		pod.Spec.Overhead = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}
+
+		requests := EffectiveRequests(pod)
+		Expect(requests.Cpu().MilliValue()).To(Equal(int64(2250)))
+	})
+
+	It("should report the effective requests before and after the decision", func() {
+		decision := Decide(pod, nil, classes, testOvercommitConfig)
+
+		Expect(decision.EffectiveBefore.Cpu().MilliValue()).To(Equal(int64(2000)))
+		Expect(decision.EffectiveAfter.Cpu().MilliValue()).To(Equal(int64(1000)))
+		Expect(decision.Containers).To(ContainElement(HaveField("Sidecar", BeTrue())))
+		savings := Savings(decision)
+		Expect(savings.Cpu().MilliValue()).To(Equal(int64(1000)))
+	})
+
+	It("should skip the run-to-completion init containers", func() {
+		classes[0].Spec.InitContainers = &overcommit.InitContainersSpec{Skip: true}
+		decision := Decide(pod, nil, classes, testOvercommitConfig)
+
+		Expect(decision.Skipped).To(ConsistOf(
+			SkippedContainer{Name: "setup", InitContainer: true, Reason: ReasonInitContainer},
+			SkippedContainer{Name: "migrate", InitContainer: true, Reason: ReasonInitContainer},
+		))
+		Expect(decision.Containers).To(HaveLen(2))
+		// The setup init container keeps the effective CPU request of the pod
+		savings := Savings(decision)
+		Expect(savings).NotTo(HaveKey(corev1.ResourceCPU))
+		Expect(savings.Memory().Value()).To(Equal(int64(128 << 20)))
+	})
+
+	It("should apply the ratios of the run-to-completion init containers", func() {
+		classes[0].Spec.InitContainers = &overcommit.InitContainersSpec{
+			Ratios: map[corev1.ResourceName]float64{corev1.ResourceCPU: 0.25},
+		}
+		decision := Decide(pod, nil, classes, testOvercommitConfig)
+
+		Expect(decision.Containers).To(HaveLen(4))
+		expected := map[string]int64{"app": 500, "setup": 500, "proxy": 250, "migrate": 250}
+		for _, container := range decision.Containers {
+			Expect(container.After.Cpu().MilliValue()).To(Equal(expected[container.Name]), container.Name)
+		}
+		// cpu: max(setup 500m, migrate 250m + proxy 250m, app 500m + proxy 250m)
+		Expect(decision.EffectiveAfter.Cpu().MilliValue()).To(Equal(int64(750)))
+	})
+})
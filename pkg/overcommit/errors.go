@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"errors"
+	"fmt"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+// FailureReason is the reason code of a pod whose overcommit couldn't be decided.
+type FailureReason string
+
+const (
+	// FailureOvercommitUnavailable means the Overcommit resource couldn't be read.
+	FailureOvercommitUnavailable FailureReason = "OvercommitUnavailable"
+	// FailureClassesUnavailable means the OvercommitClasses couldn't be listed.
+	FailureClassesUnavailable FailureReason = "ClassesUnavailable"
+	// FailureNamespaceUnavailable means the namespace of the pod couldn't be read.
+	FailureNamespaceUnavailable FailureReason = "NamespaceUnavailable"
+	// FailureOwnerUnavailable means the root owner of the pod couldn't be read.
+	FailureOwnerUnavailable FailureReason = "OwnerUnavailable"
+	// FailureAuthorizationUnavailable means the permission of an opt-out couldn't be checked.
+	FailureAuthorizationUnavailable FailureReason = "AuthorizationUnavailable"
+	// FailureAudit means the audited decision couldn't be recorded on the pod.
+	FailureAudit FailureReason = "AuditFailed"
+	// FailureRecord means the original requests couldn't be recorded on the pod.
+	FailureRecord FailureReason = "RecordFailed"
+	// FailureUnknown is the reason of the errors that aren't a ResolutionError.
+	FailureUnknown FailureReason = "Unknown"
+)
+
+// ResolutionError is a failure to gather or record what the overcommit of a pod is decided
+// from. Its reason tells which one.
+type ResolutionError struct {
+	Reason FailureReason
+	Err    error
+}
+
+// NewResolutionError returns a ResolutionError of the reason wrapping err.
+func NewResolutionError(reason FailureReason, err error) *ResolutionError {
+	return &ResolutionError{Reason: reason, Err: err}
+}
+
+func (e *ResolutionError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Reason, e.Err)
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// Reason returns the reason of the ResolutionError in the chain of err, FailureUnknown when
+// there is none.
+func Reason(err error) FailureReason {
+	var resolutionErr *ResolutionError
+	if errors.As(err, &resolutionErr) {
+		return resolutionErr.Reason
+	}
+	return FailureUnknown
+}
+
+// OnErrorPolicy returns the onError policy of the class with the name, Admit when the class
+// doesn't exist or doesn't set it.
+func OnErrorPolicy(classes []overcommit.OvercommitClass, name string) overcommit.OnError {
+	if class := findClass(classes, name); class != nil && class.Spec.OnError != "" {
+		return class.Spec.OnError
+	}
+	return overcommit.OnErrorAdmit
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("ResolutionError", func() {
+	It("should be found in the chain of the error", func() {
+		cause := errors.New("the cache is not synced")
+		err := fmt.Errorf("error deciding the overcommit: %w", NewResolutionError(FailureClassesUnavailable, cause))
+
+		Expect(Reason(err)).To(Equal(FailureClassesUnavailable))
+		Expect(err).To(MatchError(cause))
+		Expect(err.Error()).To(ContainSubstring("ClassesUnavailable: the cache is not synced"))
+		Expect(Reason(cause)).To(Equal(FailureUnknown))
+	})
+
+	It("should take the onError policy of the class, Admit by default", func() {
+		classes := []overcommit.OvercommitClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "strict"}, Spec: overcommit.OvercommitClassSpec{OnError: overcommit.OnErrorDeny}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "unset"}},
+		}
+
+		Expect(OnErrorPolicy(classes, "strict")).To(Equal(overcommit.OnErrorDeny))
+		Expect(OnErrorPolicy(classes, "unset")).To(Equal(overcommit.OnErrorAdmit))
+		Expect(OnErrorPolicy(classes, "missing")).To(Equal(overcommit.OnErrorAdmit))
+		Expect(OnErrorPolicy(nil, "strict")).To(Equal(overcommit.OnErrorAdmit))
+	})
+})
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"slices"
+	"strings"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// OptOutAnnotation set to "true" on a pod opts it out of the overcommit. It is only honored when
+// the user that creates the pod is allowed the OptOutVerb on the OvercommitClass, or its service
+// account when the class sets ExclusionsSpec.ServiceAccountOptOut.
+const OptOutAnnotation = "overcommit.inditex.dev/opt-out"
+
+// OptOutVerb is the RBAC verb on an OvercommitClass that allows opting its pods out.
+const OptOutVerb = "opt-out"
+
+// Reasons why a pod is left untouched.
+const (
+	ReasonOptOut        = "opt-out"
+	ReasonOperatorPod   = "operator pod"
+	ReasonOwnerKind     = "excluded owner kind"
+	ReasonPriorityClass = "excluded priority class"
+	ReasonGuaranteed    = "guaranteed qos"
+)
+
+// OptedOut tells whether the pod asks to be opted out of the overcommit.
+func OptedOut(pod *corev1.Pod) bool {
+	return pod.Annotations[OptOutAnnotation] == "true"
+}
+
+// ServiceAccountOptOut tells whether the class honors the opt-out of the pods whose service
+// account is allowed the OptOutVerb.
+func ServiceAccountOptOut(class *overcommit.OvercommitClass) bool {
+	return class.Spec.Exclusions != nil && class.Spec.Exclusions.ServiceAccountOptOut
+}
+
+// NeedsOwner tells whether the exclusions of the class depend on the root owner of the pod,
+// which the caller has to resolve. The owner of a mirror pod, its Node, is its root owner.
+func NeedsOwner(pod *corev1.Pod, class *overcommit.OvercommitClass) bool {
+	return class.Spec.Exclusions != nil && len(class.Spec.Exclusions.OwnerKinds) > 0 &&
+		len(pod.OwnerReferences) > 0 && ownerKind(pod) != "Node"
+}
+
+// Excluded returns the reason the exclusions of the class leave the pod untouched, or an empty
+// string. rootOwnerKind is the kind of the root owner of the pod, as returned by
+// resolver.Resolver.PodOwner, and is only needed when NeedsOwner.
+func Excluded(pod *corev1.Pod, class *overcommit.OvercommitClass, rootOwnerKind string) string {
+	exclusions := class.Spec.Exclusions
+	if exclusions == nil {
+		return ""
+	}
+
+	kinds := []string{strings.SplitN(rootOwnerKind, "/", 2)[0]}
+	if owner := ownerKind(pod); owner != "" {
+		kinds = append(kinds, owner)
+	}
+	for _, kind := range kinds {
+		if slices.Contains(exclusions.OwnerKinds, kind) {
+			return ReasonOwnerKind
+		}
+	}
+	if pod.Spec.PriorityClassName != "" && slices.Contains(exclusions.PriorityClassNames, pod.Spec.PriorityClassName) {
+		return ReasonPriorityClass
+	}
+	if exclusions.Guaranteed && guaranteed(pod) {
+		return ReasonGuaranteed
+	}
+	return ""
+}
+
+// ownerKind returns the kind of the controller of the pod. The static pods mirrored by the
+// kubelet are owned by their Node.
+func ownerKind(pod *corev1.Pod) string {
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return "Node"
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller {
+			return owner.Kind
+		}
+	}
+	if len(pod.OwnerReferences) > 0 {
+		return pod.OwnerReferences[0].Kind
+	}
+	return ""
+}
+
+// guaranteed tells whether the pod is of the Guaranteed QoS class: every container sets CPU and
+// memory limits, and its requests of them, when set, are the limits.
+func guaranteed(pod *corev1.Pod) bool {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for _, container := range containers {
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				limit, hasLimit := container.Resources.Limits[name]
+				if !hasLimit {
+					return false
+				}
+				if request, hasRequest := container.Resources.Requests[name]; hasRequest && request.Cmp(limit) != 0 {
+					return false
+				}
+			}
+		}
+	}
+	return len(pod.Spec.Containers) > 0
+}
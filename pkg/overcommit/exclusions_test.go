@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("Exclusions", func() {
+	var (
+		pod   *corev1.Pod
+		class *overcommit.OvercommitClass
+	)
+
+	BeforeEach(func() {
+		class = testClasses[0].DeepCopy()
+		class.Spec.Exclusions = &overcommit.ExclusionsSpec{
+			OwnerKinds:         []string{"DaemonSet", "Job", "Node"},
+			PriorityClassNames: []string{"system-node-critical"},
+			Guaranteed:         true,
+		}
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "test-container",
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("1"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should not exclude the pods of a class without exclusions", func() {
+		class.Spec.Exclusions = nil
+		pod.Spec.PriorityClassName = "system-node-critical"
+		Expect(Excluded(pod, class, "DaemonSet/apps/v1")).To(BeEmpty())
+		Expect(NeedsOwner(pod, class)).To(BeFalse())
+	})
+
+	It("should exclude the pods by their owner or their root owner", func() {
+		Expect(Excluded(pod, class, "pod")).To(BeEmpty())
+		Expect(Excluded(pod, class, "DaemonSet/apps/v1")).To(Equal(ReasonOwnerKind))
+
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "backup-123", Controller: ptr.To(true)}}
+		Expect(NeedsOwner(pod, class)).To(BeTrue())
+		Expect(Excluded(pod, class, "CronJob/batch/v1")).To(Equal(ReasonOwnerKind))
+	})
+
+	It("should exclude the mirror pods with the Node kind", func() {
+		pod.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"}
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "Node", Name: "node-1", Controller: ptr.To(true)}}
+		Expect(NeedsOwner(pod, class)).To(BeFalse())
+		Expect(Excluded(pod, class, "")).To(Equal(ReasonOwnerKind))
+	})
+
+	It("should exclude the pods by their priority class", func() {
+		pod.Spec.PriorityClassName = "system-node-critical"
+		Expect(Excluded(pod, class, "")).To(Equal(ReasonPriorityClass))
+	})
+
+	It("should exclude the Guaranteed pods", func() {
+		Expect(Excluded(pod, class, "")).To(BeEmpty())
+
+		pod.Spec.Containers[0].Resources.Requests = nil
+		Expect(Excluded(pod, class, "")).To(Equal(ReasonGuaranteed))
+
+		pod.Spec.InitContainers = []corev1.Container{{Name: "init"}}
+		Expect(Excluded(pod, class, "")).To(BeEmpty())
+	})
+
+	It("should take the opt-out annotation", func() {
+		Expect(OptedOut(pod)).To(BeFalse())
+		pod.Annotations = map[string]string{OptOutAnnotation: "true"}
+		Expect(OptedOut(pod)).To(BeTrue())
+	})
+
+	It("should only authorize the service account opt-out when the class allows it", func() {
+		Expect(ServiceAccountOptOut(class)).To(BeFalse())
+		class.Spec.Exclusions.ServiceAccountOptOut = true
+		Expect(ServiceAccountOptOut(class)).To(BeTrue())
+		class.Spec.Exclusions = nil
+		Expect(ServiceAccountOptOut(class)).To(BeFalse())
+	})
+})
@@ -3,121 +3,198 @@
 //
 // SPDX-License-Identifier: Apache-2.0
 
+// Package overcommit calculates the overcommitted requests of a pod. It has no
+// dependency on a Kubernetes client: the caller provides the pod, its namespace,
+// the OvercommitClasses and the Overcommit configuration, and gets a Decision back.
 package overcommit
 
 import (
-	"context"
-	"os"
-
-	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
-
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	"k8s.io/client-go/tools/record"
-	"sigs.k8s.io/controller-runtime/pkg/client"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-var podlog = logf.Log.WithName("overcommit")
-
-func mutateContainers(containers []corev1.Container, pod *corev1.Pod, cpuValue float64, memoryValue float64) {
-	for i, container := range containers {
-		limits := container.Resources.Limits
-		requests := container.Resources.Requests
-		// If the container doesn't have limits, don't mutate the container
-		if limits == nil {
-			podlog.Info(
-				"Limits is nil, don't mutate the container",
-				"containerName", container.Name, "generateName", pod.GenerateName,
-			)
-		} else if cpuValue == 1 && memoryValue == 1 {
-			podlog.Info(
-				"Container didn't mutate the cpu and memory",
-				"generateName", pod.GenerateName, "cpuValue", cpuValue, "memoryValue", memoryValue,
-			)
-		} else {
-			if cpuLimit, ok := limits[corev1.ResourceCPU]; ok {
-				// If the cpu overcommit value is 1, don't mutate the container
-				if cpuValue == 1 {
-					podlog.Info(
-						"Container didn't mutate the cpu",
-						"generateName", pod.GenerateName, "cpuValue", cpuValue,
-					)
-				}
-				newCPURequest := float64(cpuLimit.MilliValue()) * cpuValue
-				requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(newCPURequest), resource.DecimalSI)
-			}
-			if memoryLimit, ok := limits[corev1.ResourceMemory]; ok {
-				// If the memory overcommit value is 1, don't mutate the container
-				if memoryValue == 1 {
-					podlog.Info(
-						"Container didn't mutate the memory",
-						"generateName", pod.GenerateName, "memoryValue", memoryValue,
-					)
-				}
-				newMemoryRequest := float64(memoryLimit.Value()) * memoryValue
-				requests[corev1.ResourceMemory] = *resource.NewQuantity(int64(newMemoryRequest), resource.BinarySI)
-			}
-			containers[i].Resources.Requests = requests
-		}
+// Decide resolves the OvercommitClass of the pod and calculates the new requests of its
+// containers and init containers, and the effective requests of the pod before and after them.
+// The pod is not modified, see Apply. The previous labels are
+// honored after the overcommitLabel during a label migration.
+func Decide(pod *corev1.Pod, namespace *corev1.Namespace, classes []overcommit.OvercommitClass, overcommitConfig overcommit.OvercommitSpec, previousLabels ...string) Decision {
+	class, source := resolveClass(pod, namespace, classes, append([]string{overcommitConfig.OvercommitLabel}, previousLabels...)...)
+	decision := Decision{Class: class, Source: source}
+	if class == nil {
+		return decision
 	}
+
+	decision.RequestPolicy = requestPolicy(pod, class.Spec)
+	decision.Mode = class.Spec.Mode
+	if decision.Mode == "" {
+		decision.Mode = overcommit.ModeEnforce
+	}
+	decision.decideContainers(pod.Spec.Containers, false, class.Spec)
+	decision.decideContainers(pod.Spec.InitContainers, true, class.Spec)
+
+	after := pod.DeepCopy()
+	Apply(after, decision)
+	decision.EffectiveBefore = EffectiveRequests(pod)
+	decision.EffectiveAfter = EffectiveRequests(after)
+	return decision
 }
 
-func makeOvercommit(pod *corev1.Pod, cpuValue float64, memoryValue float64) {
-	mutateContainers(pod.Spec.Containers, pod, cpuValue, memoryValue)
-	podlog.Info(
-		"Containers mutated", "generateName", pod.GenerateName, "cpuValue", cpuValue, "memoryValue", memoryValue,
-	)
+// resourceRatio is the overcommit ratio of a resource.
+type resourceRatio struct {
+	name  corev1.ResourceName
+	ratio float64
 }
 
-func makeOvercommitInitContainers(pod *corev1.Pod, cpuValue float64, memoryValue float64) {
-	mutateContainers(pod.Spec.InitContainers, pod, cpuValue, memoryValue)
-	podlog.Info(
-		"InitContainers mutated", "generateName", pod.GenerateName, "cpuValue", cpuValue, "memoryValue", memoryValue,
-	)
+// ratios returns the ratios set in the class: CPU, memory and ephemeral-storage.
+func ratios(spec overcommit.OvercommitClassSpec) []resourceRatio {
+	all := []resourceRatio{
+		{corev1.ResourceCPU, spec.CpuOvercommit},
+		{corev1.ResourceMemory, spec.MemoryOvercommit},
+		{corev1.ResourceEphemeralStorage, spec.EphemeralStorageOvercommit},
+	}
+
+	result := make([]resourceRatio, 0, len(all))
+	for _, r := range all {
+		if r.ratio > 0 {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// allOne reports whether applying the ratios leaves the limits untouched.
+func allOne(ratios []resourceRatio) bool {
+	for _, r := range ratios {
+		if r.ratio != 1 {
+			return false
+		}
+	}
+	return true
 }
 
-func Overcommit(pod *corev1.Pod, recorder record.EventRecorder, client client.Client) {
-	ctx := context.Background()
-	podlog.Info("Mutating Pod", "generateGame", pod.GenerateName)
-	metrics.K8sOvercommitOperatorPodsRequestedTotal.WithLabelValues(os.Getenv("OVERCOMMIT_CLASS_NAME")).Inc()
-
-	// Get the overcommit values from the labels
-	cpuValue, memoryValue := checkOvercommitType(ctx, *pod, client)
-	// Check if the values are valid
-
-	// Multiplicate the limits by the overcommit value and set the new value as request
-	makeOvercommit(pod, cpuValue, memoryValue)
-	podlog.Info(
-		"Pod mutated", "generateName", pod.GenerateName, "cpuValue", cpuValue, "memoryValue", memoryValue,
-	)
-
-	// If it has initContainers, make the overcommit
-	podlog.Info("cheking if pod has initContainer")
-	if len(pod.Spec.InitContainers) > 0 {
-		podlog.Info("Pod has initContainers, mutating them", "generateName", pod.GenerateName)
-		makeOvercommitInitContainers(pod, cpuValue, memoryValue)
+func (d *Decision) decideContainers(containers []corev1.Container, initContainers bool, class overcommit.OvercommitClassSpec) {
+	rules := containerRules(class)
+
+	for _, container := range containers {
+		sidecar := initContainers && Sidecar(container)
+		spec := class
+		// Run-to-completion init containers follow the initContainers settings of the class
+		if initContainers && !sidecar && class.InitContainers != nil {
+			if class.InitContainers.Skip {
+				d.Skipped = append(d.Skipped, SkippedContainer{Name: container.Name, InitContainer: true, Reason: ReasonInitContainer})
+				continue
+			}
+			spec = withRatios(spec, class.InitContainers.Ratios)
+		}
+
+		rule := matchRule(rules, container)
+		ruleName := ""
+		if rule != nil {
+			ruleName = rule.Name
+		}
+		skipped := SkippedContainer{Name: container.Name, InitContainer: initContainers, Sidecar: sidecar, Rule: ruleName}
+		if rule != nil && rule.Exclude {
+			skipped.Reason = ReasonExcludedByRule
+			d.Skipped = append(d.Skipped, skipped)
+			continue
+		}
+		spec = withRule(spec, rule)
+		ratios := ratios(spec)
+		if allOne(ratios) {
+			skipped.Reason = ReasonOvercommitOne
+			d.Skipped = append(d.Skipped, skipped)
+			continue
+		}
+
+		after := container.Resources.Requests.DeepCopy()
+		if after == nil {
+			after = corev1.ResourceList{}
+		}
+		baselines := map[corev1.ResourceName]BaselineSource{}
+		var clamps []Clamp
+		explicit := false
+		for _, r := range ratios {
+			existing, hasRequest := container.Resources.Requests[r.name]
+			if hasRequest && d.RequestPolicy == overcommit.RequestPolicyRespectExplicit {
+				explicit = true
+				continue
+			}
+			base, source, ok := baseline(container.Resources, r.name, spec.Baseline[r.name])
+			if !ok {
+				continue
+			}
+			var limit *resource.Quantity
+			if l, hasLimit := container.Resources.Limits[r.name]; hasLimit {
+				limit = &l
+			}
+			quantity, resourceClamps := request(r.name, base, limit, r.ratio, spec)
+			clamps = append(clamps, resourceClamps...)
+			if hasRequest && d.RequestPolicy == overcommit.RequestPolicyOnlyLower && quantity.Cmp(existing) > 0 {
+				clamps = append(clamps, Clamp{Resource: r.name, Reason: ClampExistingRequest, Calculated: quantity})
+				quantity = existing
+			}
+			after[r.name] = quantity
+			baselines[r.name] = source
+		}
+
+		// If there is nothing to apply the overcommit to, don't mutate the container
+		if len(baselines) == 0 {
+			reason := ReasonNoBaseline
+			switch {
+			case explicit:
+				reason = ReasonExplicitRequests
+			case container.Resources.Limits == nil:
+				reason = ReasonNoLimits
+			}
+			skipped.Reason = reason
+			d.Skipped = append(d.Skipped, skipped)
+			continue
+		}
+
+		d.Containers = append(d.Containers, ContainerDecision{
+			Name:          container.Name,
+			InitContainer: initContainers,
+			Sidecar:       sidecar,
+			Before:        container.Resources.Requests.DeepCopy(),
+			After:         after,
+			Baselines:     baselines,
+			Clamps:        clamps,
+			Rule:          ruleName,
+		})
 	}
+}
 
-	// Increment the metric K8sOvercommitOperatorMutatedPodsTotal
-	metrics.K8sOvercommitOperatorMutatedPodsTotal.WithLabelValues(os.Getenv("OVERCOMMIT_CLASS_NAME")).Inc()
-
-	// Add an event to the pod
-	recorder.Eventf(
-		pod,
-		corev1.EventTypeNormal,
-		"OvercommitApplied",
-		"Applied overcommit to containers of Pod '%s': OvercommitClass = %s, CPU Overcommit = %.2f, Memory Overcommit = %.2f",
-		pod.Name,
-		os.Getenv("OVERCOMMIT_CLASS_NAME"),
-		cpuValue,
-		memoryValue,
-	)
-	if cpuValue == 1 && memoryValue == 1 {
-		metrics.K8sOvercommitOperatorPodsNotMutatedTotal.WithLabelValues(
-			os.Getenv("OVERCOMMIT_CLASS_NAME"), pod.GenerateName, pod.Namespace, "overcommit values = 1",
-		).Inc()
+// baseline returns the value of the resource the ratio is applied to and where it was taken from.
+// It returns false when the container doesn't set the value the mode needs.
+func baseline(resources corev1.ResourceRequirements, name corev1.ResourceName, mode overcommit.BaselineMode) (resource.Quantity, BaselineSource, bool) {
+	limit, hasLimit := resources.Limits[name]
+	request, hasRequest := resources.Requests[name]
+
+	switch mode {
+	case overcommit.BaselineFromRequests:
+		return request, BaselineRequests, hasRequest
+	case overcommit.BaselineMax:
+		if hasRequest && (!hasLimit || request.Cmp(limit) > 0) {
+			return request, BaselineRequests, true
+		}
+		return limit, BaselineLimits, hasLimit
+	default:
+		return limit, BaselineLimits, hasLimit
+	}
+}
 
+// Apply sets the requests calculated in the decision on the containers of the pod.
+func Apply(pod *corev1.Pod, decision Decision) {
+	for _, containerDecision := range decision.Containers {
+		containers := pod.Spec.Containers
+		if containerDecision.InitContainer {
+			containers = pod.Spec.InitContainers
+		}
+		for i := range containers {
+			if containers[i].Name == containerDecision.Name {
+				containers[i].Resources.Requests = containerDecision.After.DeepCopy()
+			}
+		}
 	}
-	podlog.Info("Pod mutated", "generateName", pod.GenerateName, "cpuValue", cpuValue, "memoryValue", memoryValue)
 }
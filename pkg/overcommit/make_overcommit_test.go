@@ -6,13 +6,13 @@
 package overcommit
 
 import (
-	"os"
-
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 )
 
 var _ = Describe("Overcommit", func() {
@@ -50,43 +50,215 @@ var _ = Describe("Overcommit", func() {
 		}
 	})
 
-	Describe("mutateContainers", func() {
-		It("should mutate container requests based on overcommit values", func() {
-			mutateContainers(pod.Spec.Containers, pod, 0.5, 0.5)
+	Describe("Decide", func() {
+		It("should calculate the container requests based on overcommit values", func() {
+			decision := Decide(pod, nil, testClasses, testOvercommitConfig)
 
-			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
+			Expect(decision.ClassName()).To(Equal("test-class"))
+			Expect(decision.Source).To(Equal(SourcePodLabel))
+			Expect(decision.Containers).To(HaveLen(1))
+			Expect(decision.Containers[0].Before).To(BeEmpty())
+			Expect(decision.Containers[0].After).To(Equal(expectedRequests))
+			Expect(decision.Containers[0].Baselines).To(Equal(map[corev1.ResourceName]BaselineSource{
+				corev1.ResourceCPU:    BaselineLimits,
+				corev1.ResourceMemory: BaselineLimits,
+			}))
+			Expect(decision.Mutated()).To(BeTrue())
 		})
 
-		It("should not mutate containers if limits are nil", func() {
-			pod.Spec.Containers[0].Resources.Limits = nil
-			mutateContainers(pod.Spec.Containers, pod, 0.5, 0.5)
+		It("should not modify the pod", func() {
+			Decide(pod, nil, testClasses, testOvercommitConfig)
 
 			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
 		})
-	})
 
-	Describe("makeOvercommit", func() {
-		It("should apply overcommit to containers", func() {
-			makeOvercommit(pod, 0.5, 0.5)
+		It("should skip containers if limits are nil", func() {
+			pod.Spec.Containers[0].Resources.Limits = nil
+			decision := Decide(pod, nil, testClasses, testOvercommitConfig)
 
-			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
+			Expect(decision.Mutated()).To(BeFalse())
+			Expect(decision.Skipped).To(ConsistOf(SkippedContainer{Name: "test-container", Reason: ReasonNoLimits}))
+		})
+
+		It("should skip containers if overcommit values are 1", func() {
+			testClasses[0].Spec.CpuOvercommit, testClasses[0].Spec.MemoryOvercommit = 1, 1
+			defer func() {
+				testClasses[0].Spec.CpuOvercommit, testClasses[0].Spec.MemoryOvercommit = 0.5, 0.5
+			}()
+			decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+			Expect(decision.Mutated()).To(BeFalse())
+			Expect(decision.Skipped).To(ConsistOf(SkippedContainer{Name: "test-container", Reason: ReasonOvercommitOne}))
 		})
-	})
 
-	Describe("Overcommit", func() {
-		BeforeEach(func() {
-			os.Setenv("OVERCOMMIT_CLASS_NAME", "test-class")
+		It("should calculate the init container requests", func() {
+			pod.Spec.InitContainers = []corev1.Container{*pod.Spec.Containers[0].DeepCopy()}
+			pod.Spec.InitContainers[0].Name = "test-init-container"
+			decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+			Expect(decision.Containers).To(HaveLen(2))
+			Expect(decision.Containers[1].Name).To(Equal("test-init-container"))
+			Expect(decision.Containers[1].InitContainer).To(BeTrue())
 		})
 
-		AfterEach(func() {
-			os.Unsetenv("OVERCOMMIT_CLASS_NAME")
+		It("should calculate the ephemeral-storage requests and leave the other resources untouched", func() {
+			testClasses[0].Spec.EphemeralStorageOvercommit = 0.5
+			defer func() {
+				testClasses[0].Spec.EphemeralStorageOvercommit = 0
+			}()
+			pod.Spec.Containers[0].Resources.Limits[corev1.ResourceEphemeralStorage] = resource.MustParse("2Gi")
+			pod.Spec.Containers[0].Resources.Limits["nvidia.com/gpu"] = resource.MustParse("2")
+			pod.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse("2")
+			decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+			after := decision.Containers[0].After
+			Expect(after).To(HaveKeyWithValue(corev1.ResourceEphemeralStorage, *resource.NewQuantity(1073741824, resource.BinarySI)))
+			Expect(after).To(HaveKeyWithValue(corev1.ResourceName("nvidia.com/gpu"), resource.MustParse("2")))
 		})
 
-		It("should mutate pod containers and record an event", func() {
-			Overcommit(pod, recorder, k8sClient)
+		Context("with a baseline mode", func() {
+			BeforeEach(func() {
+				testClasses[0].Spec.Baseline = map[corev1.ResourceName]overcommit.BaselineMode{
+					corev1.ResourceCPU:    overcommit.BaselineFromRequests,
+					corev1.ResourceMemory: overcommit.BaselineMax,
+				}
+			})
+
+			AfterEach(func() {
+				testClasses[0].Spec.Baseline = nil
+			})
+
+			It("should scale down the requests of containers without limits", func() {
+				pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				}
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.Containers).To(HaveLen(1))
+				Expect(decision.Containers[0].After).To(Equal(expectedRequests))
+				Expect(decision.Containers[0].Baselines).To(Equal(map[corev1.ResourceName]BaselineSource{
+					corev1.ResourceCPU:    BaselineRequests,
+					corev1.ResourceMemory: BaselineRequests,
+				}))
+			})
+
+			It("should use the highest of the limit and the request with max", func() {
+				pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				}
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.Containers[0].After).To(Equal(expectedRequests))
+				Expect(decision.Containers[0].Baselines).To(HaveKeyWithValue(corev1.ResourceMemory, BaselineLimits))
+			})
+
+			It("should leave untouched the resources without baseline", func() {
+				pod.Spec.Containers[0].Resources.Requests = nil
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.Containers[0].After).NotTo(HaveKey(corev1.ResourceCPU))
+				Expect(decision.Containers[0].Baselines).To(Equal(map[corev1.ResourceName]BaselineSource{
+					corev1.ResourceMemory: BaselineLimits,
+				}))
+			})
+
+			It("should skip containers without any baseline", func() {
+				testClasses[0].Spec.Baseline[corev1.ResourceMemory] = overcommit.BaselineFromRequests
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.Mutated()).To(BeFalse())
+				Expect(decision.Skipped).To(ConsistOf(SkippedContainer{Name: "test-container", Reason: ReasonNoBaseline}))
+			})
+		})
+
+		Context("with a request policy", func() {
+			BeforeEach(func() {
+				pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("100m"),
+				}
+			})
+
+			AfterEach(func() {
+				testClasses[0].Spec.RequestPolicy = ""
+			})
+
+			It("should override the existing requests by default", func() {
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.RequestPolicy).To(Equal(overcommit.RequestPolicyOverride))
+				Expect(decision.Containers[0].After).To(Equal(expectedRequests))
+			})
+
+			It("should never raise an existing request with OnlyLower", func() {
+				testClasses[0].Spec.RequestPolicy = overcommit.RequestPolicyOnlyLower
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.Containers[0].After).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("100m")))
+				Expect(decision.Containers[0].After).To(HaveKeyWithValue(corev1.ResourceMemory, expectedRequests[corev1.ResourceMemory]))
+				Expect(decision.Containers[0].Clamps).To(ConsistOf(Clamp{
+					Resource:   corev1.ResourceCPU,
+					Reason:     ClampExistingRequest,
+					Calculated: expectedRequests[corev1.ResourceCPU],
+				}))
+			})
+
+			It("should only fill in the missing requests with RespectExplicit", func() {
+				testClasses[0].Spec.RequestPolicy = overcommit.RequestPolicyRespectExplicit
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.Containers[0].After).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("100m")))
+				Expect(decision.Containers[0].Baselines).NotTo(HaveKey(corev1.ResourceCPU))
+				Expect(decision.Containers[0].After).To(HaveKeyWithValue(corev1.ResourceMemory, expectedRequests[corev1.ResourceMemory]))
+			})
+
+			It("should skip containers with all the requests set with RespectExplicit", func() {
+				testClasses[0].Spec.RequestPolicy = overcommit.RequestPolicyRespectExplicit
+				pod.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory] = resource.MustParse("1Gi")
+				decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+
+				Expect(decision.Mutated()).To(BeFalse())
+				Expect(decision.Skipped).To(ConsistOf(SkippedContainer{Name: "test-container", Reason: ReasonExplicitRequests}))
+			})
+
+			It("should apply a stricter policy from the pod annotation", func() {
+				testClasses[0].Spec.RequestPolicy = overcommit.RequestPolicyOnlyLower
+				pod.Annotations = map[string]string{RequestPolicyAnnotation: string(overcommit.RequestPolicyRespectExplicit)}
+				Expect(Decide(pod, nil, testClasses, testOvercommitConfig).RequestPolicy).To(Equal(overcommit.RequestPolicyRespectExplicit))
+			})
+
+			It("should ignore a laxer policy from the pod annotation", func() {
+				testClasses[0].Spec.RequestPolicy = overcommit.RequestPolicyOnlyLower
+				pod.Annotations = map[string]string{RequestPolicyAnnotation: string(overcommit.RequestPolicyOverride)}
+				Expect(Decide(pod, nil, testClasses, testOvercommitConfig).RequestPolicy).To(Equal(overcommit.RequestPolicyOnlyLower))
+			})
+		})
+
+		It("should return no class if none can be resolved", func() {
+			pod.Labels = nil
+			decision := Decide(pod, nil, testClasses[:1], testOvercommitConfig)
+
+			Expect(decision.Class).To(BeNil())
+			Expect(decision.Source).To(Equal(SourceNone))
+			Expect(decision.Mutated()).To(BeFalse())
+		})
+	})
+
+	Describe("Apply", func() {
+		It("should apply overcommit to containers", func() {
+			Apply(pod, Decide(pod, nil, testClasses, testOvercommitConfig))
 
 			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
+		})
 
+		It("should set the requests of containers without requests", func() {
+			pod.Spec.Containers[0].Resources.Requests = nil
+			Apply(pod, Decide(pod, nil, testClasses, testOvercommitConfig))
+
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
 		})
 	})
 })
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The annotations set on the pods whose requests were changed by a class.
+const (
+	// OriginalRequestsAnnotation holds the requests of the changed containers before the
+	// overcommit, per container, as JSON.
+	OriginalRequestsAnnotation = "overcommit.inditex.dev/original-requests"
+	// ClassAnnotation holds the name of the class that changed the requests.
+	ClassAnnotation = "overcommit.inditex.dev/class"
+	// ClassGenerationAnnotation holds the generation of the class that changed the requests.
+	ClassGenerationAnnotation = "overcommit.inditex.dev/class-generation"
+	// RatiosAnnotation holds the ratios of the class, per resource, as JSON.
+	RatiosAnnotation = "overcommit.inditex.dev/ratios"
+	// SourceAnnotation holds where the class was resolved from.
+	SourceAnnotation = "overcommit.inditex.dev/resolution-source"
+)
+
+// RecordAnnotations are the annotations Record sets.
+var RecordAnnotations = []string{
+	OriginalRequestsAnnotation,
+	ClassAnnotation,
+	ClassGenerationAnnotation,
+	RatiosAnnotation,
+	SourceAnnotation,
+}
+
+// RevertAnnotation set to "true" on a namespace or on the template of the pods of a workload
+// restores the future pods to their original requests: the overcommit isn't applied to them,
+// and the requests recorded by a previous overcommit are set back.
+const RevertAnnotation = "overcommit.inditex.dev/revert"
+
+// Record returns the annotations that record on the pod the requests of the containers changed
+// by the decision before the overcommit, the class that changed them and how it was resolved.
+func Record(decision Decision) (map[string]string, error) {
+	original := make(map[string]corev1.ResourceList, len(decision.Containers))
+	for _, container := range decision.Containers {
+		original[container.Name] = container.Before
+		if original[container.Name] == nil {
+			original[container.Name] = corev1.ResourceList{}
+		}
+	}
+	rawOriginal, err := json.Marshal(original)
+	if err != nil {
+		return nil, NewResolutionError(FailureRecord, fmt.Errorf("error encoding the original requests: %w", err))
+	}
+
+	classRatios := map[corev1.ResourceName]string{}
+	for _, r := range ratios(decision.Class.Spec) {
+		classRatios[r.name] = strconv.FormatFloat(r.ratio, 'f', -1, 64)
+	}
+	rawRatios, err := json.Marshal(classRatios)
+	if err != nil {
+		return nil, NewResolutionError(FailureRecord, fmt.Errorf("error encoding the ratios: %w", err))
+	}
+
+	return map[string]string{
+		OriginalRequestsAnnotation: string(rawOriginal),
+		ClassAnnotation:            decision.ClassName(),
+		ClassGenerationAnnotation:  strconv.FormatInt(decision.Class.Generation, 10),
+		RatiosAnnotation:           string(rawRatios),
+		SourceAnnotation:           string(decision.Source),
+	}, nil
+}
+
+// Restore sets back the requests recorded in the annotations of the pod, for example on a pod
+// created from the spec of an overcommitted one, and removes the annotations. It returns the
+// class that had changed them, empty when the pod has no record.
+func Restore(pod *corev1.Pod) (string, error) {
+	raw, ok := pod.Annotations[OriginalRequestsAnnotation]
+	if !ok {
+		return "", nil
+	}
+	var original map[string]corev1.ResourceList
+	if err := json.Unmarshal([]byte(raw), &original); err != nil {
+		return "", fmt.Errorf("error decoding the %s annotation: %w", OriginalRequestsAnnotation, err)
+	}
+
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for i := range containers {
+			requests, ok := original[containers[i].Name]
+			if !ok {
+				continue
+			}
+			if len(requests) == 0 {
+				requests = nil
+			}
+			containers[i].Resources.Requests = requests
+		}
+	}
+	class := pod.Annotations[ClassAnnotation]
+	for _, annotation := range RecordAnnotations {
+		delete(pod.Annotations, annotation)
+	}
+	return class, nil
+}
+
+// RevertRequested tells whether the pod or its namespace asks for its original requests.
+func RevertRequested(pod *corev1.Pod, namespace *corev1.Namespace) bool {
+	if pod.Annotations[RevertAnnotation] == "true" {
+		return true
+	}
+	return namespace != nil && namespace.Annotations[RevertAnnotation] == "true"
+}
+
+// AuditAnnotations returns the annotations for the audit log of the API server: the keys of the
+// annotations without their prefix, which the API server replaces with the name of the webhook.
+func AuditAnnotations(annotations map[string]string) map[string]string {
+	audit := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		audit[key[strings.LastIndex(key, "/")+1:]] = value
+	}
+	return audit
+}
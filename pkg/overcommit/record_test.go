@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Record", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		testClasses[0].Generation = 3
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				Labels: map[string]string{
+					"inditex.com/overcommit-class": "test-class",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "test-container",
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("1"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("800m")},
+						},
+					},
+				},
+				InitContainers: []corev1.Container{
+					{
+						Name: "test-init-container",
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		testClasses[0].Generation = 0
+	})
+
+	It("should record the original requests and the class", func() {
+		annotations, err := Record(Decide(pod, nil, testClasses, testOvercommitConfig))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(annotations).To(Equal(map[string]string{
+			OriginalRequestsAnnotation: `{"test-container":{"cpu":"800m"},"test-init-container":{}}`,
+			ClassAnnotation:            "test-class",
+			ClassGenerationAnnotation:  "3",
+			RatiosAnnotation:           `{"cpu":"0.5","memory":"0.5"}`,
+			SourceAnnotation:           string(SourcePodLabel),
+		}))
+		Expect(AuditAnnotations(annotations)).To(HaveKeyWithValue("original-requests", annotations[OriginalRequestsAnnotation]))
+	})
+
+	It("should restore the original requests of an overcommitted pod", func() {
+		decision := Decide(pod, nil, testClasses, testOvercommitConfig)
+		annotations, err := Record(decision)
+		Expect(err).NotTo(HaveOccurred())
+		original := pod.DeepCopy()
+		Apply(pod, decision)
+		pod.Annotations = annotations
+
+		class, err := Restore(pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(class).To(Equal("test-class"))
+		Expect(pod.Spec).To(Equal(original.Spec))
+		Expect(pod.Annotations).To(BeEmpty())
+	})
+
+	It("should leave a pod without record untouched", func() {
+		original := pod.DeepCopy()
+		class, err := Restore(pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(class).To(BeEmpty())
+		Expect(pod).To(Equal(original))
+	})
+
+	It("should take the revert annotation of the pod or its namespace", func() {
+		namespace := &corev1.Namespace{}
+		Expect(RevertRequested(pod, namespace)).To(BeFalse())
+
+		namespace.Annotations = map[string]string{RevertAnnotation: "true"}
+		Expect(RevertRequested(pod, namespace)).To(BeTrue())
+
+		pod.Annotations = map[string]string{RevertAnnotation: "true"}
+		Expect(RevertRequested(pod, nil)).To(BeTrue())
+	})
+})
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RequestPolicyAnnotation lets a pod choose a request policy stricter than the one of its class.
+const RequestPolicyAnnotation = "overcommit.inditex.dev/request-policy"
+
+// strictness orders the request policies from the one that changes the most requests
+// to the one that changes the fewest.
+var strictness = map[overcommit.RequestPolicy]int{
+	overcommit.RequestPolicyOverride:        0,
+	overcommit.RequestPolicyOnlyLower:       1,
+	overcommit.RequestPolicyRespectExplicit: 2,
+}
+
+// requestPolicy returns the policy of the class, or the one of the pod annotation when it is
+// stricter. Unknown values and empty policies are treated as Override.
+func requestPolicy(pod *corev1.Pod, class overcommit.OvercommitClassSpec) overcommit.RequestPolicy {
+	policy := class.RequestPolicy
+	if _, ok := strictness[policy]; !ok {
+		policy = overcommit.RequestPolicyOverride
+	}
+
+	podPolicy := overcommit.RequestPolicy(pod.Annotations[RequestPolicyAnnotation])
+	if podStrictness, ok := strictness[podPolicy]; ok && podStrictness > strictness[policy] {
+		policy = podPolicy
+	}
+	return policy
+}
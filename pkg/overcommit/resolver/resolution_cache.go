@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package resolver gathers from the cluster the inputs of an overcommit.Decide call.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	decision "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var logger = logf.Log.WithName("resolver")
+
+// Resolver provides the cluster state needed to decide the overcommit of a pod. Its errors are
+// overcommit.ResolutionErrors telling what couldn't be read.
+type Resolver interface {
+	Overcommit(ctx context.Context) (*overcommit.Overcommit, error)
+	Classes(ctx context.Context) ([]overcommit.OvercommitClass, error)
+	Namespace(ctx context.Context, name string) (*corev1.Namespace, error)
+	PodOwner(ctx context.Context, pod *corev1.Pod) (string, string, error)
+}
+
+// ownerKinds are the owner kinds whose metadata informers are started up front, so the
+// usual owner chains are resolved without lazily starting an informer during an admission.
+var ownerKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1", Kind: "CronJob"},
+}
+
+var namespaceGVK = corev1.SchemeGroupVersion.WithKind("Namespace")
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=replicasets;deployments;statefulsets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch
+
+// ResolutionCache resolves the Overcommit label, the OvercommitClasses, the namespace labels
+// and the pod owners from shared informers, so an admission never calls the API server.
+// Namespaces and owners are watched as metadata only to keep the memory footprint small.
+type ResolutionCache struct {
+	reader client.Reader
+	cache  cache.Cache
+	synced atomic.Bool
+}
+
+var _ Resolver = &ResolutionCache{}
+
+// NewResolutionCache returns a ResolutionCache backed by the given informer cache.
+// It must be started (see Start) before its ReadyCheck reports ready.
+func NewResolutionCache(c cache.Cache) *ResolutionCache {
+	return &ResolutionCache{reader: c, cache: c}
+}
+
+// Start registers the informers used by the resolution and blocks until they are synced.
+// It implements manager.Runnable.
+func (r *ResolutionCache) Start(ctx context.Context) error {
+	informerObjects := []client.Object{
+		&overcommit.Overcommit{},
+		&overcommit.OvercommitClass{},
+		metadataObject(namespaceGVK),
+	}
+	for _, gvk := range ownerKinds {
+		informerObjects = append(informerObjects, metadataObject(gvk))
+	}
+
+	for _, obj := range informerObjects {
+		if _, err := r.cache.GetInformer(ctx, obj); err != nil {
+			return fmt.Errorf("error starting the informer for %T: %w", obj, err)
+		}
+	}
+
+	if !r.cache.WaitForCacheSync(ctx) {
+		return errors.New("timed out waiting for the resolution cache to sync")
+	}
+	r.synced.Store(true)
+	logger.Info("Resolution cache synced")
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every webhook replica needs its own cache.
+func (r *ResolutionCache) NeedLeaderElection() bool {
+	return false
+}
+
+// ReadyCheck is a healthz.Checker that fails until the informers are synced, so no
+// admission is routed to the webhook before it can resolve from memory.
+func (r *ResolutionCache) ReadyCheck(_ *http.Request) error {
+	if !r.synced.Load() {
+		return errors.New("resolution cache not synced yet")
+	}
+	return nil
+}
+
+// Overcommit returns the Overcommit "cluster" resource.
+func (r *ResolutionCache) Overcommit(ctx context.Context) (*overcommit.Overcommit, error) {
+	var overcommitObject overcommit.Overcommit
+	if err := r.reader.Get(ctx, client.ObjectKey{Name: "cluster"}, &overcommitObject); err != nil {
+		return nil, decision.NewResolutionError(decision.FailureOvercommitUnavailable, fmt.Errorf("error getting Overcommit with name '%s': %w", "cluster", err))
+	}
+	return &overcommitObject, nil
+}
+
+// Classes returns every OvercommitClass.
+func (r *ResolutionCache) Classes(ctx context.Context) ([]overcommit.OvercommitClass, error) {
+	var overcommitClasses overcommit.OvercommitClassList
+	if err := r.reader.List(ctx, &overcommitClasses); err != nil {
+		return nil, decision.NewResolutionError(decision.FailureClassesUnavailable, fmt.Errorf("error listing OvercommitClass: %w", err))
+	}
+	return overcommitClasses.Items, nil
+}
+
+// Namespace returns the namespace with the given name. Only its metadata is cached.
+func (r *ResolutionCache) Namespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	namespace := metadataObject(namespaceGVK)
+	if err := r.reader.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		return nil, decision.NewResolutionError(decision.FailureNamespaceUnavailable, fmt.Errorf("error getting the namespace: %w", err))
+	}
+	return &corev1.Namespace{ObjectMeta: namespace.ObjectMeta}, nil
+}
+
+// PodOwner returns the name and kind of the root owner of the pod. It keeps the contract of
+// utils.GetPodOwner: Deployments are reported through their ReplicaSet, pods without owner as "pod".
+func (r *ResolutionCache) PodOwner(ctx context.Context, pod *corev1.Pod) (string, string, error) {
+	name, kind, err := r.podOwner(ctx, pod)
+	if err != nil {
+		return "", "", decision.NewResolutionError(decision.FailureOwnerUnavailable, err)
+	}
+	return name, kind, nil
+}
+
+func (r *ResolutionCache) podOwner(ctx context.Context, pod *corev1.Pod) (string, string, error) {
+	if len(pod.OwnerReferences) == 0 {
+		return pod.Name, "pod", nil
+	}
+
+	ownerRef := pod.OwnerReferences[0] // Assume the first owner reference is the relevant one
+	owner, err := r.getOwner(ctx, ownerRef, pod.Namespace)
+	if err != nil {
+		return "", "", err
+	}
+
+	// If the owner is a ReplicaSet, report its Deployment
+	if ownerRef.Kind == "ReplicaSet" {
+		if len(owner.OwnerReferences) == 0 {
+			return "", "", fmt.Errorf("replicaSet %s has no owner", owner.Name)
+		}
+		rsOwnerRef := owner.OwnerReferences[0]
+		if rsOwnerRef.Kind == "Deployment" {
+			return rsOwnerRef.Name, rsOwnerRef.Kind, nil
+		}
+		return "", "", fmt.Errorf("replicaSet %s owner is not a Deployment", owner.Name)
+	}
+
+	// Otherwise walk up to the root owner
+	for len(owner.OwnerReferences) > 0 {
+		ownerRef = owner.OwnerReferences[0]
+		owner, err = r.getOwner(ctx, ownerRef, pod.Namespace)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to find root owner: %w", err)
+		}
+	}
+	return ownerRef.Name, ownerRef.Kind + "/" + ownerRef.APIVersion, nil
+}
+
+func (r *ResolutionCache) getOwner(ctx context.Context, ref metav1.OwnerReference, namespace string) (*metav1.PartialObjectMetadata, error) {
+	owner := metadataObject(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	err := r.reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner %s %s: %w", ref.Kind, ref.Name, err)
+	}
+	return owner, nil
+}
+
+func metadataObject(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
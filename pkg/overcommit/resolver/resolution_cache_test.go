@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resolver
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolutionCache", func() {
+	It("should be ready once the informers are synced", func() {
+		Expect(resolution.ReadyCheck(nil)).To(Succeed())
+		Expect(NewResolutionCache(informers).ReadyCheck(nil)).NotTo(Succeed())
+	})
+
+	It("should resolve the overcommit from memory", func() {
+		oc, err := resolution.Overcommit(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(oc.Spec.OvercommitLabel).To(Equal("inditex.com/overcommit-class"))
+	})
+
+	It("should resolve the classes from memory", func() {
+		classes, err := resolution.Classes(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(classes).To(HaveLen(1))
+		Expect(classes[0].Spec.CpuOvercommit).To(Equal(0.5))
+		Expect(classes[0].Spec.IsDefault).To(BeTrue())
+	})
+
+	It("should resolve the namespace labels from memory", func() {
+		namespace, err := resolution.Namespace(context.TODO(), "test-namespace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespace.Labels).To(HaveKeyWithValue("inditex.com/overcommit-class", "test-class"))
+	})
+
+	It("should report pods without owner as pod", func() {
+		name, kind, err := resolution.PodOwner(context.TODO(), testPod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("test-pod"))
+		Expect(kind).To(Equal("pod"))
+	})
+})